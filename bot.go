@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/bot"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"os"
+	"time"
+)
+
+func runBot(args []string) error {
+	fs := flag.NewFlagSet("bot", flag.ExitOnError)
+	mode := fs.String("mode", model.Easy.Name, "difficulty preset: easy, medium or hard")
+	size := fs.Int("size", 0, "custom board size, overrides the preset")
+	holes := fs.Int("holes", 0, "custom black holes count, overrides the preset")
+	seed := fs.Int64("seed", time.Now().UnixMilli(), "seed of the first game, every new game takes the next one")
+	code := fs.String("code", "", "board code, every game is played on this board")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	difficulty, err := model.DifficultyByName(*mode)
+	if err != nil {
+		return err
+	}
+	difficulty = difficulty.Custom(*size, *holes)
+
+	newBoard := func() (model.Board, error) {
+		board, err := model.NewBoard(model.SeededCoordinatesProvider{Seed: *seed}, difficulty.Size, difficulty.BlackHolesCount)
+		*seed++
+		return board, err
+	}
+	if *code != "" {
+		c, err := model.ParseCode(*code)
+		if err != nil {
+			return err
+		}
+		newBoard = c.NewBoard
+	}
+	return bot.NewSession(newBoard).Run(os.Stdin, os.Stdout)
+}
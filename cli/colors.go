@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"os"
+	"strings"
+)
+
+// ColorMode adapts the colours of any theme to what the player can see and the terminal can show.
+type ColorMode string
+
+const (
+	// FullColor draws the theme as it is.
+	FullColor    ColorMode = "full"
+	Deuteranopia ColorMode = "deuteranopia"
+	Protanopia   ColorMode = "protanopia"
+	Tritanopia   ColorMode = "tritanopia"
+	// Monochrome relies only on the glyphs, bold and reverse video.
+	Monochrome ColorMode = "mono"
+)
+
+var colorModes = []ColorMode{FullColor, Deuteranopia, Protanopia, Tritanopia, Monochrome}
+
+// minColors is the fewest terminal colours telling the 8 numbers apart from the background,
+// terminals with less get Monochrome.
+const minColors = 16
+
+// safePalette are the colours of 1 to 8, won and lost for a colour vision deficiency.
+type safePalette struct {
+	numbers   [8]string
+	won, lost string
+}
+
+// okabeIto is the Okabe-Ito palette, it keeps apart for both red-green deficiencies.
+var okabeIto = safePalette{
+	numbers: [8]string{"#0072b2", "#e69f00", "#d55e00", "#cc79a7", "#56b4e9", "#009e73", "", "#999999"},
+	won:     "#0072b2",
+	lost:    "#d55e00",
+}
+
+var safePalettes = map[ColorMode]safePalette{
+	Deuteranopia: okabeIto,
+	Protanopia:   okabeIto,
+	// Tritanopia mixes blue with green and yellow with violet, reds and teals stay apart
+	Tritanopia: {
+		numbers: [8]string{"#cc3311", "#009988", "#ee3377", "#332288", "#bb5566", "#117733", "", "#999999"},
+		won:     "#009988",
+		lost:    "#cc3311",
+	},
+}
+
+// ParseColorMode checks the name of a colour mode, empty is FullColor.
+func ParseColorMode(name string) (ColorMode, error) {
+	if name == "" {
+		return FullColor, nil
+	}
+	for _, m := range colorModes {
+		if string(m) == strings.ToLower(name) {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown colour mode %q, want one of %s", name, ColorModeNames())
+}
+
+func ColorModeNames() string {
+	names := make([]string, 0, len(colorModes))
+	for _, m := range colorModes {
+		names = append(names, string(m))
+	}
+	return strings.Join(names, ", ")
+}
+
+// screenColorMode falls back to Monochrome when the terminal has few colours or NO_COLOR is set.
+func screenColorMode(s tcell.Screen, m ColorMode) ColorMode {
+	if s.Colors() < minColors || os.Getenv("NO_COLOR") != "" {
+		return Monochrome
+	}
+	return m
+}
+
+// withColors returns the theme adapted to the colour mode.
+func (t Theme) withColors(m ColorMode) Theme {
+	switch m {
+	case Monochrome:
+		base := tcell.StyleDefault
+		t.Base = base
+		t.Closed = base.Dim(true)
+		t.Flag = base.Bold(true)
+		t.BlackHole = base.Bold(true).Reverse(true)
+		t.Cursor = base.Reverse(true)
+		t.Banner = base
+		t.Won = base.Bold(true)
+		t.Lost = base.Bold(true).Reverse(true)
+		for i := range t.Numbers {
+			t.Numbers[i] = base
+		}
+		return t
+	}
+	p, ok := safePalettes[m]
+	if !ok {
+		return t
+	}
+	color := func(name string, fallback tcell.Style) tcell.Style {
+		if name == "" {
+			return fallback
+		}
+		return t.Base.Foreground(tcell.GetColor(name))
+	}
+	for i := range t.Numbers {
+		// bold numbers don't depend on the colour alone
+		t.Numbers[i] = color(p.numbers[i], t.Base).Bold(true)
+	}
+	t.Won = color(p.won, t.Won)
+	t.Lost = color(p.lost, t.Lost)
+	t.Flag = color(p.lost, t.Flag).Bold(true)
+	return t
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"testing"
+)
+
+// fakeScreen is a terminal with the given colours and character set that can't display the missing runes.
+type fakeScreen struct {
+	tcell.Screen
+	colors  int
+	charset string
+	missing []rune
+}
+
+func (s fakeScreen) Colors() int {
+	return s.colors
+}
+
+func (s fakeScreen) CharacterSet() string {
+	return s.charset
+}
+
+func (s fakeScreen) CanDisplay(r rune, _ bool) bool {
+	for _, m := range s.missing {
+		if r == m {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseColorMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ColorMode
+		wantErr bool
+	}{
+		{name: "", want: FullColor},
+		{name: "full", want: FullColor},
+		{name: "Deuteranopia", want: Deuteranopia},
+		{name: "protanopia", want: Protanopia},
+		{name: "tritanopia", want: Tritanopia},
+		{name: "MONO", want: Monochrome},
+		{name: "sepia", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseColorMode(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColorMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColorMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScreenColorMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		colors  int
+		noColor string
+		mode    ColorMode
+		want    ColorMode
+	}{
+		{name: "full colour", colors: 256, mode: FullColor, want: FullColor},
+		{name: "colour blind", colors: 16, mode: Tritanopia, want: Tritanopia},
+		{name: "8 colours", colors: 8, mode: Deuteranopia, want: Monochrome},
+		{name: "no colours", colors: 0, mode: FullColor, want: Monochrome},
+		{name: "NO_COLOR", colors: 256, noColor: "1", mode: FullColor, want: Monochrome},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			if got := screenColorMode(fakeScreen{colors: tt.colors}, tt.mode); got != tt.want {
+				t.Errorf("screenColorMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTheme_withColors(t *testing.T) {
+	classic := BuiltinThemes()[0]
+	tests := []struct {
+		mode ColorMode
+		// wantNumbers are the foreground colours of 1 to 8
+		wantNumbers [8]tcell.Color
+		wantBold    bool
+		wantLost    tcell.Color
+	}{
+		{
+			mode: FullColor,
+			wantNumbers: [8]tcell.Color{tcell.ColorBlue, tcell.ColorGreen, tcell.ColorRed, tcell.ColorNavy, tcell.ColorMaroon, tcell.ColorTeal,
+				tcell.ColorBlack, tcell.ColorGray},
+			wantLost: tcell.ColorRed,
+		},
+		{
+			mode: Deuteranopia,
+			wantNumbers: [8]tcell.Color{tcell.NewHexColor(0x0072b2), tcell.NewHexColor(0xe69f00), tcell.NewHexColor(0xd55e00), tcell.NewHexColor(0xcc79a7),
+				tcell.NewHexColor(0x56b4e9), tcell.NewHexColor(0x009e73), tcell.ColorBlack, tcell.NewHexColor(0x999999)},
+			wantBold: true,
+			wantLost: tcell.NewHexColor(0xd55e00),
+		},
+		{
+			mode: Tritanopia,
+			wantNumbers: [8]tcell.Color{tcell.NewHexColor(0xcc3311), tcell.NewHexColor(0x009988), tcell.NewHexColor(0xee3377), tcell.NewHexColor(0x332288),
+				tcell.NewHexColor(0xbb5566), tcell.NewHexColor(0x117733), tcell.ColorBlack, tcell.NewHexColor(0x999999)},
+			wantBold: true,
+			wantLost: tcell.NewHexColor(0xcc3311),
+		},
+		{
+			mode: Monochrome,
+			wantNumbers: [8]tcell.Color{tcell.ColorDefault, tcell.ColorDefault, tcell.ColorDefault, tcell.ColorDefault, tcell.ColorDefault, tcell.ColorDefault,
+				tcell.ColorDefault, tcell.ColorDefault},
+			wantLost: tcell.ColorDefault,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			got := classic.withColors(tt.mode)
+			for i, s := range got.Numbers {
+				fg, _, attrs := s.Decompose()
+				if fg != tt.wantNumbers[i] || (attrs&tcell.AttrBold != 0) != tt.wantBold {
+					t.Errorf("Numbers[%d] = %v, bold %v, want %v, bold %v", i, fg, attrs&tcell.AttrBold != 0, tt.wantNumbers[i], tt.wantBold)
+				}
+			}
+			if lost := foreground(got.Lost); lost != tt.wantLost {
+				t.Errorf("Lost = %v, want %v", lost, tt.wantLost)
+			}
+		})
+	}
+}
+
+func TestTheme_withColorsMonochrome(t *testing.T) {
+	got := BuiltinThemes()[0].withColors(Monochrome)
+	// without colours the cursor, the black holes and the lost banner have to stand out by the attributes
+	for name, s := range map[string]tcell.Style{"Cursor": got.Cursor, "BlackHole": got.BlackHole, "Lost": got.Lost} {
+		if fg, bg, attrs := s.Decompose(); fg != tcell.ColorDefault || bg != tcell.ColorDefault || attrs&tcell.AttrReverse == 0 {
+			t.Errorf("%s = %v, %v, %v, want the default colours in reverse video", name, fg, bg, attrs)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/save"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/score"
+)
+
+// startDaily finds out if the board is the daily challenge and if its official attempt was already made.
+func (g *Game) startDaily() {
+	g.daily, g.unranked, g.streak = "", false, 0
+	if g.difficulty.Name != model.Daily.Name {
+		return
+	}
+	date, ok := model.DailyDate(g.board.GetSeed())
+	if !ok {
+		return
+	}
+	g.daily = date.Format(score.DayLayout)
+	if g.scores == nil {
+		return
+	}
+	_, g.unranked, _ = g.scores.DailyAttempt(g.daily, g.player)
+	g.streak, _ = g.scores.DailyStreak(g.daily, g.player)
+}
+
+// recordDaily records the official attempt, the later games of the day are retries.
+func (g *Game) recordDaily(o score.Outcome) {
+	if g.unranked {
+		return
+	}
+	res, err := g.scores.RecordDaily(g.daily, o)
+	g.result, g.scoreError = &res.Result, err
+	g.streak = res.Streak
+	g.unranked = true
+}
+
+// forfeitDaily records the official attempt as lost when it's left unfinished for another board.
+func (g *Game) forfeitDaily() {
+	if g.daily == "" || g.unranked || g.scores == nil || g.board.IsPractice() ||
+		g.board.GetState() != model.InProgress || g.board.GetMoves() == 0 {
+		return
+	}
+	o := g.outcome()
+	o.Won, o.LostX, o.LostY = false, -1, -1
+	g.recordDaily(o)
+}
+
+// forfeitSaved records the official attempt of a saved daily challenge that is discarded as lost.
+func (g *Game) forfeitSaved(saved save.Game) {
+	if saved.Difficulty.Name != model.Daily.Name {
+		return
+	}
+	board, d := g.board, g.difficulty
+	g.board, g.difficulty, g.metrics = saved.Board, saved.Difficulty, saved.Board.Metrics()
+	g.startDaily()
+	g.forfeitDaily()
+	g.board, g.difficulty, g.metrics = board, d, board.Metrics()
+	g.startDaily()
+}
+
+func (g *Game) dailyStatus() string {
+	if g.unranked {
+		return "Daily retry"
+	}
+	return fmt.Sprintf("Daily  Streak: %d", g.streak)
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/save"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/score"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// safeCell returns the name of a cell that isn't a black hole.
+func safeCell(t *testing.T, b *model.Board) string {
+	t.Helper()
+	for y := 0; y < b.GetSize(); y++ {
+		for x := 0; x < b.GetSize(); x++ {
+			if !b.IsBlackHole(x, y) {
+				return fmt.Sprintf("%s%d", columnName(x), y+1)
+			}
+		}
+	}
+	t.Fatalf("no safe cell")
+	return ""
+}
+
+func dailyConfig(t *testing.T) Config {
+	code := model.DailyCode(time.Now())
+	scores := score.NewStore(filepath.Join(t.TempDir(), "scores.json"))
+	return Config{Difficulty: model.Daily, Player: "tramp", Scores: &scores, Code: &code}
+}
+
+func TestDaily_unfinishedAttemptIsLost(t *testing.T) {
+	tests := []struct {
+		name string
+		// leave leaves the daily challenge in the middle
+		leave func(t *testing.T, cfg Config)
+	}{
+		{
+			name: "line mode quit",
+			leave: func(t *testing.T, cfg Config) {
+				l, err := NewLineGame(cfg, nil, io.Discard)
+				if err != nil {
+					t.Fatalf("NewLineGame() error = %v", err)
+				}
+				l.in = strings.NewReader("open " + safeCell(t, &l.game.board) + "\nquit\n")
+				if err := l.Start(); err != nil {
+					t.Fatalf("Start() error = %v", err)
+				}
+			},
+		},
+		{
+			name: "autosave discarded",
+			leave: func(t *testing.T, cfg Config) {
+				saves := save.NewStore(t.TempDir())
+				cfg.Saves = &saves
+				board, err := cfg.Code.NewBoard()
+				if err != nil {
+					t.Fatalf("NewBoard() error = %v", err)
+				}
+				l, err := NewLineGame(cfg, strings.NewReader(""), io.Discard)
+				if err != nil {
+					t.Fatalf("NewLineGame() error = %v", err)
+				}
+				x, y, _ := parseCellName(safeCell(t, &board), board.GetSize())
+				board.Open(x, y)
+				g := l.game
+				g.autosaved = &save.Game{Difficulty: model.Daily, Player: "tramp", Board: board}
+				g.handleResume(tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := dailyConfig(t)
+			tt.leave(t, cfg)
+			day := time.Now().UTC().Format(score.DayLayout)
+			a, ok, err := cfg.Scores.DailyAttempt(day, "tramp")
+			if err != nil || !ok || a.Won {
+				t.Errorf("DailyAttempt() = %+v, %v, %v, want a lost attempt", a, ok, err)
+			}
+		})
+	}
+}
+
+func TestDaily_pastedCode(t *testing.T) {
+	cfg := dailyConfig(t)
+	// -code plays the boards of the codes as custom ones
+	cfg.Difficulty = model.CustomDifficulty(cfg.Code.Size, cfg.Code.BlackHolesCount)
+	for i, wantUnranked := range []bool{false, true} {
+		l, err := NewLineGame(cfg, nil, io.Discard)
+		if err != nil {
+			t.Fatalf("NewLineGame() error = %v", err)
+		}
+		if l.game.daily == "" || l.game.difficulty != model.Daily || l.game.unranked != wantUnranked {
+			t.Errorf("game %d: daily %q, difficulty %v, unranked %v, want the daily challenge, unranked %v",
+				i, l.game.daily, l.game.difficulty, l.game.unranked, wantUnranked)
+		}
+		l.in = strings.NewReader("open " + safeCell(t, &l.game.board) + "\nquit\n")
+		if err := l.Start(); err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+	}
+}
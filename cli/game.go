@@ -1,10 +1,16 @@
 package cli
 
 import (
+	"fmt"
 	"github.com/gdamore/tcell/v2"
 	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/race"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/save"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/score"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -15,35 +21,352 @@ const BannerWidth = 70
 const BannerHeight = 4
 const BannerPadding = 10
 
+type view int
+
+const (
+	boardView view = iota
+	scoresView
+	statsView
+	menuView
+	slotsView
+	resumeView
+	raceView
+)
+
+type Config struct {
+	Difficulty model.Difficulty
+	Player     string
+	// Scores is where won games are recorded, nil disables high scores.
+	Scores *score.Store
+	// Saves keeps the saved games, nil disables saving.
+	Saves *save.Store
+	// Replays is the directory every game is recorded to, empty disables recording.
+	Replays string
+	// Practice allows to undo opens, practice games don't count for high scores and statistics.
+	Practice bool
+	// Code starts the board of the code instead of a random one.
+	Code *model.Code
+	// Keys are the key bindings, the default preset if empty.
+	Keys Bindings
+	// Themes can be switched in the game, the built-in ones if empty. Theme is the one to start with.
+	Themes []Theme
+	Theme  string
+	// Colors adapts the themes, terminals with few colours get Monochrome anyway.
+	Colors ColorMode
+	Glyphs GlyphSet
+	// Screen is the screen to play on, the terminal of the process if nil. NewGame initializes it.
+	// Term is the terminal type of the screen, TERM if empty.
+	Screen tcell.Screen
+	Term   string
+	// Race plays the races of the host instead of own games, StartRace is set for the host only.
+	Race      *race.Client
+	StartRace func() error
+}
+
 type Game struct {
-	screen   tcell.Screen
-	board    model.Board
-	location point
-	cursor   point
+	screen     tcell.Screen
+	board      model.Board
+	location   point
+	cursor     point
+	metrics    model.Metrics
+	difficulty model.Difficulty
+	player     string
+	scores     *score.Store
+	result     *score.Result
+	scoreError error
+	saves      *save.Store
+	// autosaved is the game found on launch, offered to be resumed
+	autosaved *save.Game
+	slotsMode slotsMode
+	slotsInfo string
+	// notice is shown on the banner of the board until the next key
+	notice string
+	// confirmRestart is set by the first restart key of a started game, the next key has to confirm it
+	confirmRestart bool
+	replays        string
+	recorder       *replay.Recorder
+	practice       bool
+	view           view
+	menuItem       int
+	keys           Bindings
+	theme          Theme
+	themes         []Theme
+	colors         ColorMode
+	glyphs         glyphs
+	// count is the number typed before a move, jump sends the next move to the board edge
+	count int
+	jump  bool
+	// prompt is the cell typed in the go to prompt
+	prompting   bool
+	prompt      string
+	promptError string
+	// daily is the day of the daily challenge board, empty for other boards
+	daily string
+	// unranked is set for the daily challenge retries after the official attempt
+	unranked bool
+	streak   int
+	// mu guards the game state between the input handling and the drawing goroutines
+	mu sync.Mutex
+	// symbols mirror the board, kept up to date by the board events instead of rescanning every frame
+	symbols     [][]rune
+	unsubscribe func()
+	// boardEvents counts the events of the board, the moves that didn't change it aren't recorded
+	boardEvents int
+	redraw      chan struct{}
+	// done is set by quit, Start returns and the drawing stops
+	done bool
+	race *race.Client
+	// startRaceFunc starts the race of the host, nil for the players who joined
+	startRaceFunc func() error
+	racing        bool
+	countdown     int
+	// lobby lists the players waiting for the race, racers their progress and ranking the result of the last race
+	lobby     []race.Player
+	racers    []race.Player
+	ranking   []race.Player
+	raceError string
 }
 
-func NewGame(boardSize, blackHolesCount int) (Game, error) {
+func NewGame(cfg Config) (*Game, error) {
 
-	rcp := model.RandomCoordinatesProvider{Seed: time.Now().UnixMilli()}
-	board, err := model.NewBoard(rcp, boardSize, blackHolesCount)
+	board, err := configBoard(cfg)
 	if err != nil {
-		return Game{}, err
+		return nil, err
 	}
 
-	s, err := tcell.NewScreen()
-	if err != nil {
-		return Game{}, err
+	s := cfg.Screen
+	if s == nil {
+		if s, err = tcell.NewScreen(); err != nil {
+			return nil, err
+		}
 	}
 	if err := s.Init(); err != nil {
-		return Game{}, err
+		return nil, err
 	}
 
-	return Game{
+	g, err := newGame(cfg, board, s)
+	if err != nil {
+		s.Fini()
+		return nil, err
+	}
+	if g.saves != nil {
+		// a board given by the code is only resumed if the unfinished game is the same board
+		if autosaved, err := g.saves.Load(save.Autosave); err == nil {
+			if cfg.Code == nil || autosaved.Board.GetSeed() == board.GetSeed() {
+				g.autosaved = &autosaved
+				g.view = resumeView
+			} else {
+				// it's overwritten by the next unfinished game
+				g.forfeitSaved(autosaved)
+			}
+		}
+	}
+	return g, nil
+}
+
+// configBoard returns the board of the code if there is one, a new board of the difficulty otherwise.
+func configBoard(cfg Config) (model.Board, error) {
+	if cfg.Code != nil {
+		return cfg.Code.NewBoard()
+	}
+	return newBoard(cfg.Difficulty)
+}
+
+// newGame sets the game up for the screen, the line mode has no screen.
+func newGame(cfg Config, board model.Board, s tcell.Screen) (*Game, error) {
+	g := &Game{
 		screen:   s,
-		board:    board,
-		location: point{x: (BannerWidth - boardSize) / 2, y: BannerHeight},
-		cursor:   point{x: (BannerWidth - boardSize) / 2, y: BannerHeight},
-	}, nil
+		colors:   cfg.Colors,
+		glyphs:   unicodeGlyphs,
+		player:   cfg.Player,
+		scores:   cfg.Scores,
+		saves:    cfg.Saves,
+		replays:  cfg.Replays,
+		practice: cfg.Practice,
+		keys:     cfg.Keys,
+		race:     cfg.Race,
+	}
+	if s != nil {
+		term := cfg.Term
+		if term == "" {
+			term = os.Getenv("TERM")
+		}
+		g.colors, g.glyphs = screenColorMode(s, cfg.Colors), screenGlyphs(s, cfg.Glyphs, term)
+	}
+	if g.keys.actions == nil {
+		g.keys, _ = NewBindings(DefaultPreset, nil)
+	}
+	if g.themes = cfg.Themes; len(g.themes) == 0 {
+		g.themes = BuiltinThemes()
+	}
+	name := cfg.Theme
+	if name == "" {
+		name = DefaultTheme
+	}
+	i := findTheme(g.themes, name)
+	if i < 0 {
+		return nil, fmt.Errorf("unknown theme %q, want one of %s", name, ThemeNames(g.themes))
+	}
+	g.theme = g.themes[i].withColors(g.colors)
+	// the code of today's daily challenge plays the daily challenge, one official attempt a day
+	if cfg.Code != nil && *cfg.Code == model.DailyCode(time.Now()) {
+		cfg.Difficulty = model.Daily
+	}
+	board.SetPractice(g.practice)
+	g.setBoard(board, cfg.Difficulty)
+	if g.race != nil {
+		g.startRaceFunc = cfg.StartRace
+		g.view = raceView
+	}
+	return g, nil
+}
+
+func newBoard(d model.Difficulty) (model.Board, error) {
+	seed := time.Now().UnixMilli()
+	return model.NewBoard(model.SeededCoordinatesProvider{Seed: seed}, d.Size, d.BlackHolesCount)
+}
+
+// setBoard starts playing the board, it's either a new one or a loaded one.
+func (g *Game) setBoard(board model.Board, d model.Difficulty) {
+	g.forfeitDaily()
+	g.replaceBoard(board)
+	g.difficulty = d
+	g.startDaily()
+	g.metrics = board.Metrics()
+	g.location = point{x: (BannerWidth - d.Size) / 2, y: BannerHeight}
+	g.cursor = g.location
+	g.result, g.scoreError = nil, nil
+	g.view = boardView
+	g.startRecording()
+}
+
+// replaceBoard swaps the board keeping the rest of the game as it is.
+func (g *Game) replaceBoard(board model.Board) {
+	if g.unsubscribe != nil {
+		g.unsubscribe()
+	}
+	g.board = board
+	g.unsubscribe = g.board.Subscribe(g.onBoardEvent)
+	g.symbols = make([][]rune, board.GetSize())
+	for x := range g.symbols {
+		g.symbols[x] = make([]rune, board.GetSize())
+		for y := range g.symbols[x] {
+			g.symbols[x][y] = g.glyphs.symbol(&g.board, x, y)
+		}
+	}
+	g.requestRedraw()
+}
+
+func (g *Game) onBoardEvent(e model.Event) {
+	g.boardEvents++
+	switch e := e.(type) {
+	case model.CellsOpened:
+		g.updateSymbols(e.Cells)
+		g.sendProgress()
+	case model.CellsClosed:
+		g.updateSymbols(e.Cells)
+	case model.CellFlagged:
+		g.symbols[e.X][e.Y] = g.glyphs.symbol(&g.board, e.X, e.Y)
+	case model.StateChanged:
+		if e.To == model.InProgress {
+			g.result, g.scoreError = nil, nil
+		} else if e.From == model.InProgress {
+			g.finish()
+			g.sendProgress()
+		}
+	}
+	g.requestRedraw()
+}
+
+func (g *Game) updateSymbols(cells []model.Point) {
+	for _, p := range cells {
+		g.symbols[p.X()][p.Y()] = g.glyphs.symbol(&g.board, p.X(), p.Y())
+	}
+}
+
+// requestRedraw wakes the drawing goroutine up, it never blocks.
+func (g *Game) requestRedraw() {
+	if g.redraw == nil {
+		return
+	}
+	select {
+	case g.redraw <- struct{}{}:
+	default:
+	}
+}
+
+// startRecording starts a new replay log for the current board, the game goes on without it if that fails.
+func (g *Game) startRecording() {
+	g.stopRecording()
+	if g.replays == "" {
+		return
+	}
+	r, err := replay.NewRecorder(g.replays, replay.Header{
+		Difficulty: g.difficulty,
+		Player:     g.player,
+		StartedAt:  time.Now(),
+		Board:      g.board,
+	})
+	if err == nil {
+		g.recorder = r
+	}
+}
+
+func (g *Game) stopRecording() {
+	if g.recorder != nil {
+		g.recorder.Close()
+		g.recorder = nil
+	}
+}
+
+func (g *Game) record(a replay.Action, x, y int) {
+	if g.recorder != nil {
+		g.recorder.Record(a, x, y)
+	}
+}
+
+// recordMove plays the move on the board and records it only when the board changed.
+func (g *Game) recordMove(a replay.Action, x, y int, move func(x, y int)) {
+	events := g.boardEvents
+	move(x, y)
+	if g.boardEvents != events {
+		g.record(a, x, y)
+	}
+}
+
+// restart replaces the board with a new one of the same difficulty.
+func (g *Game) restart() {
+	board, err := newBoard(g.difficulty)
+	if code, ok := g.board.Code(); ok && g.daily != "" {
+		board, err = code.NewBoard()
+	}
+	if err != nil {
+		return
+	}
+	board.SetPractice(g.practice)
+	g.setBoard(board, g.difficulty)
+}
+
+// quit keeps an unfinished game to be resumed on the next launch, Start returns once the event is handled.
+// An unfinished daily challenge that can't be resumed counts as lost.
+func (g *Game) quit() {
+	g.screen.Fini()
+	g.stopRecording()
+	if g.race != nil {
+		g.race.Close()
+	}
+	saved := false
+	if g.saves != nil {
+		if g.board.GetState() == model.InProgress && g.board.GetMoves() > 0 {
+			saved = g.saves.Save(save.Autosave, g.savedGame()) == nil
+		} else if g.autosaved == nil {
+			g.saves.Delete(save.Autosave)
+		}
+	}
+	if !saved {
+		g.forfeitDaily()
+	}
+	g.done = true
 }
 
 type point struct {
@@ -51,104 +374,337 @@ type point struct {
 	y int
 }
 
+// Start plays until the player quits or the screen is finalized.
 func (g *Game) Start() {
 
-	defStyle := tcell.StyleDefault.Background(tcell.ColorWhiteSmoke).Foreground(tcell.ColorBlack)
-	g.screen.SetStyle(defStyle)
+	g.redraw = make(chan struct{}, 1)
 
-	go g.printScreen(defStyle)
+	go g.printScreen()
+	if g.race != nil {
+		go g.followRace()
+	}
 
 	for {
 		switch event := g.screen.PollEvent().(type) {
+		case nil:
+			// the screen was finalized elsewhere, like on a closed SSH session
+			g.mu.Lock()
+			g.quit()
+			g.mu.Unlock()
+			return
 		case *tcell.EventResize:
 			g.screen.Sync()
 		case *tcell.EventKey:
+			g.mu.Lock()
 			g.handleEventKey(event)
+			done := g.done
+			g.mu.Unlock()
+			if done {
+				return
+			}
+			g.requestRedraw()
 		}
 	}
 }
 
 func (g *Game) handleEventKey(event *tcell.EventKey) {
 
-	if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyCtrlC {
-		g.screen.Fini()
-		os.Exit(0)
+	a, bound := g.keys.action(event)
+	g.notice = ""
+	confirmed := g.confirmRestart
+	g.confirmRestart = false
+	// Ctrl-C quits whatever the bindings are
+	if event.Key() == tcell.KeyCtrlC {
+		g.quit()
+		return
+	}
+	if g.view == boardView && g.prompting {
+		g.handlePrompt(event)
+		return
+	}
+	if a == Quit {
+		g.quit()
+		return
+	}
+	if g.handleRace(event, a) {
+		return
+	}
+	if g.view == menuView && g.handleMenu(event) {
+		return
+	}
+	if g.view == resumeView {
+		g.handleResume(event)
+		return
+	}
+	if g.view == slotsView && g.handleSlots(event) {
+		return
+	}
+	if !bound {
+		if g.view == boardView && g.board.GetState() == model.InProgress && !g.board.IsPaused() {
+			g.handleCount(event)
+		}
+		return
+	}
+	n := g.repeat()
+	switch a {
+	case Menu:
+		g.toggleView(menuView)
+		return
+	case Scores:
+		g.toggleView(scoresView)
+		return
+	case Stats:
+		g.toggleView(statsView)
+		return
+	case NextTheme:
+		g.nextTheme()
+		return
+	}
+	if g.view != boardView {
+		return
+	}
+	switch {
+	case a == Pause && g.board.IsPaused():
+		g.board.Resume()
+		return
+	case a == Pause:
+		g.board.Pause()
+		return
+	case g.board.IsPaused():
+		return
+	case a == Undo || a == Redo:
+		g.handleUndo(a == Undo)
+		return
+	case a == Restart && !confirmed && g.board.GetState() == model.InProgress && g.board.GetMoves() > 0:
+		g.confirmRestart = true
+		g.notice = fmt.Sprintf("press %s again to leave this game for a new one", g.keys.key(Restart))
+		return
+	case a == Restart:
+		g.restart()
+		return
 	}
 	if g.board.GetState() != model.InProgress {
 		return
 	}
 
-	g.handleMoves(event)
+	g.handleMoves(a, n)
 }
 
-func (g *Game) handleMoves(event *tcell.EventKey) {
-	leftBoundary := g.location.x
-	rightBoundary := g.location.x + g.board.GetSize()*XAxisStep
-	topBoundary := g.location.y
-	bottomBoundary := g.location.y + g.board.GetSize()*YAxisStep
+func (g *Game) handleUndo(undo bool) {
+	x, y := g.cursorCell()
+	if undo {
+		g.recordMove(replay.Undo, x, y, func(int, int) { g.board.Undo() })
+	} else {
+		g.recordMove(replay.Redo, x, y, func(int, int) { g.board.Redo() })
+	}
+}
 
-	switch event.Key() {
-	case tcell.KeyRight:
-		if g.cursor.x < rightBoundary-XAxisStep {
-			g.cursor.x += XAxisStep
-		}
-	case tcell.KeyLeft:
-		if g.cursor.x >= leftBoundary+XAxisStep {
-			g.cursor.x -= XAxisStep
-		}
-	case tcell.KeyDown:
-		if g.cursor.y < bottomBoundary-YAxisStep {
-			g.cursor.y += YAxisStep
+func (g *Game) toggleView(v view) {
+	if g.view == v {
+		g.view = boardView
+		return
+	}
+	g.view = v
+}
+
+// handleMoves runs the action n times where repeating makes sense.
+func (g *Game) handleMoves(a Action, n int) {
+	cursor := g.cursor
+	x, y := g.cursorCell()
+	switch a {
+	case Jump:
+		g.jump = true
+	case Open:
+		if g.board.IsOpened(x, y) {
+			g.recordMove(replay.Chord, x, y, g.board.Chord)
+		} else {
+			g.recordMove(replay.Open, x, y, g.board.Open)
 		}
-	case tcell.KeyUp:
-		if g.cursor.y >= topBoundary+YAxisStep {
-			g.cursor.y -= YAxisStep
+	case Chord:
+		g.recordMove(replay.Chord, x, y, g.board.Chord)
+	case Flag:
+		g.recordMove(replay.Flag, x, y, g.board.ToggleFlag)
+	case Hint:
+		if hx, hy, ok := g.board.Hint(); ok {
+			g.setCursorCell(hx, hy)
 		}
-	case tcell.KeyRune:
-		switch event.Rune() {
-		case ' ':
-			g.board.Open((g.cursor.x-g.location.x)/XAxisStep, (g.cursor.y-g.location.y)/YAxisStep)
+	case NextClosed:
+		g.nextCell(n, g.isClosed)
+	case NextNumber:
+		g.nextCell(n, g.isUnresolved)
+	case Goto:
+		g.openPrompt()
+	default:
+		if step, ok := moves[a]; ok {
+			g.moveCursor(step, n)
 		}
 	}
+	if g.cursor != cursor {
+		x, y := g.cursorCell()
+		g.record(replay.Move, x, y)
+	}
 }
 
-func (g *Game) printScreen(s tcell.Style) {
+// cursorCell returns the board coordinates of the cell under the cursor.
+func (g *Game) cursorCell() (x, y int) {
+	return (g.cursor.x - g.location.x) / XAxisStep, (g.cursor.y - g.location.y) / YAxisStep
+}
+
+func (g *Game) printScreen() {
+	// the ticker keeps the timer running between the board events
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
 
 	for {
+		g.mu.Lock()
+		if g.done {
+			g.mu.Unlock()
+			return
+		}
+		// the theme can change between the frames
+		s := g.theme.Base
+		g.screen.SetStyle(s)
 		g.screen.Clear()
-		g.printBanner(s, "Use arrows to navigate, space to open a cell, esc to quit")
-		g.printBoard(s)
-		g.printCursor(s)
+		switch g.view {
+		case boardView:
+			help := g.boardHelp()
+			if g.race != nil && !g.prompting && !g.jump && g.count == 0 {
+				help = g.raceHelp()
+			}
+			g.printBanner(g.theme.Banner, help)
+			if g.board.IsPaused() {
+				g.printMessage(s, fmt.Sprintf("Paused at %ds, press %s to resume", int(g.board.GetElapsed().Seconds()), g.keys.key(Pause)))
+			} else {
+				g.printBoard(s)
+				g.printCursor(g.theme.Cursor)
+			}
+			g.printCode(g.theme.Banner)
+		case scoresView:
+			g.printBanner(g.theme.Banner, g.keys.help("scores:back to the game", "quit:quit"))
+			g.printScores(s)
+		case statsView:
+			g.printBanner(g.theme.Banner, g.keys.help("stats:back to the game", "quit:quit"))
+			g.printStatistics(s)
+		case menuView:
+			g.printBanner(g.theme.Banner, "arrows: select, enter: confirm, "+g.keys.help("menu:back to the game"))
+			g.printMenu(s)
+		case slotsView:
+			g.printBanner(g.theme.Banner, fmt.Sprintf("1-%d: pick a slot, %s", save.Slots, g.keys.help("menu:back to the menu")))
+			g.printSlots(s)
+		case resumeView:
+			g.printBanner(g.theme.Banner, g.keys.help("open:resume the last game", "restart:start a new one"))
+			g.printResume(s)
+		case raceView:
+			g.printBanner(g.theme.Banner, g.raceHelp())
+			g.printLobby(s)
+		}
 		g.screen.Show()
+		g.mu.Unlock()
 
-		time.Sleep(50 * time.Millisecond)
+		select {
+		case <-g.redraw:
+		case <-ticker.C:
+		}
 	}
 }
 
 func (g *Game) printBoard(s tcell.Style) {
 	for y := 0; y < g.board.GetSize(); y++ {
 		for x := 0; x < g.board.GetSize(); x++ {
-			symbol := getSymbol(&g.board, x, y)
-			g.screen.SetContent(g.location.x+x*XAxisStep, g.location.y+y*YAxisStep, symbol, nil, s)
+			symbol := g.symbols[x][y]
+			g.screen.SetContent(g.location.x+x*XAxisStep, g.location.y+y*YAxisStep, symbol, nil, g.cellStyle(x, y))
+		}
+	}
+	if g.board.GetState() == model.InProgress {
+		message := fmt.Sprintf("Time: %ds  Moves: %d  Flags: %d/%d", int(g.board.GetElapsed().Seconds()),
+			g.board.GetMoves(), g.board.GetFlagsCount(), g.board.GetBlackHolesCount())
+		if g.board.IsPractice() {
+			message += "  Practice"
+		}
+		if g.daily != "" {
+			message += "  " + g.dailyStatus()
 		}
+		g.printMessage(s, message)
 	}
 	if g.board.GetState() == model.Lost {
-		g.printMessage(s.Foreground(tcell.ColorRed), "Oops, that was a black hole. You Lost :(")
+		g.printMessage(g.theme.Lost, "Oops, that was a black hole. You Lost :(")
+		g.printResult(s)
 	}
 	if g.board.GetState() == model.Won {
-		g.printMessage(s.Foreground(tcell.ColorDarkGreen), "Great job! You've avoided all the black holes!")
+		if g.result != nil && g.result.PersonalBest {
+			g.printMessage(g.theme.Won.Bold(true), g.glyphs.star+" New personal best! You've avoided all the black holes! "+g.glyphs.star)
+		} else {
+			g.printMessage(g.theme.Won, "Great job! You've avoided all the black holes!")
+		}
+		g.printResult(s)
+	}
+}
+
+func (g *Game) printResult(s tcell.Style) {
+	seconds := g.board.GetElapsed().Seconds()
+	lines := []string{
+		fmt.Sprintf("Time: %.2fs  Moves: %d", seconds, g.board.GetMoves()),
+		fmt.Sprintf("3BV: %d  Openings: %d  Isolated numbers: %d  Required guesses: %d",
+			g.metrics.ThreeBV, g.metrics.Openings, g.metrics.IsolatedNumbers, g.metrics.Guesses),
+	}
+	if g.board.GetState() == model.Won {
+		lines[0] += fmt.Sprintf("  3BV/s: %.2f", model.ThreeBVPerSecond(g.metrics.ThreeBV, seconds))
+	}
+	if code, ok := g.board.Code(); ok {
+		lines = append(lines, fmt.Sprintf("Board code: %s, play it again with -code %[1]s", code))
+	}
+	switch {
+	case g.daily != "" && g.result == nil && g.scoreError == nil && !g.board.IsPractice():
+		lines = append(lines, "Retry of the daily challenge, it doesn't count for high scores")
+	case g.daily != "" && g.scoreError == nil && !g.board.IsPractice():
+		lines = append(lines, fmt.Sprintf("Daily challenge %s done, streak: %d", g.daily, g.streak))
+	}
+	switch {
+	case g.board.IsPractice():
+		lines = append(lines, "Practice game, it doesn't count for high scores")
+	case g.scoreError != nil:
+		lines = append(lines, fmt.Sprintf("Can't save the score: %v", g.scoreError))
+	case g.result != nil && g.result.Rank > 0:
+		lines = append(lines, fmt.Sprintf("#%d in %s high scores, press %s to see them", g.result.Rank, g.scoresKey(), g.keys.key(Scores)))
+	}
+	if g.race != nil {
+		lines = append(lines, g.raceLines()...)
+	}
+	g.printLines(s, g.location.y+g.board.GetSize()*YAxisStep+1, lines)
+}
+
+// printCode shows the board code on the bottom line of the banner.
+func (g *Game) printCode(s tcell.Style) {
+	code, ok := g.board.Code()
+	if !ok {
+		return
+	}
+	label := []rune(fmt.Sprintf(" board %s ", code))
+	for i, r := range label {
+		g.screen.SetContent(BannerWidth-len(label)-1+i, BannerHeight-1, r, nil, s)
+	}
+}
+
+func (g *Game) printLines(s tcell.Style, top int, lines []string) {
+	for i, line := range lines {
+		for j, r := range []rune(line) {
+			g.screen.SetContent(j+BannerPadding, top+i, r, nil, s)
+		}
 	}
 }
 
 func (g *Game) printCursor(s tcell.Style) {
 	x := (g.cursor.x - g.location.x) / XAxisStep
 	y := (g.cursor.y - g.location.y) / YAxisStep
-	var symbol rune
+	symbol := ' '
 	if x >= 0 && x < g.board.GetSize() && y >= 0 && y < g.board.GetSize() {
-		symbol = getSymbol(&g.board, x, y)
-		symbol = highlight(symbol)
+		symbol = g.symbols[x][y]
+	}
+	if g.glyphs.highlight == nil {
+		g.screen.SetContent(g.cursor.x-1, g.cursor.y, '[', nil, g.theme.Base)
+		g.screen.SetContent(g.cursor.x+1, g.cursor.y, ']', nil, g.theme.Base)
 	} else {
-		symbol = '〇'
+		symbol = g.glyphs.highlight(symbol)
 	}
 	g.screen.SetContent(g.cursor.x, g.cursor.y, symbol, nil, s)
 }
@@ -160,61 +716,24 @@ func (g *Game) printMessage(s tcell.Style, message string) {
 }
 
 func (g *Game) printBanner(s tcell.Style, info string) {
-	g.screen.SetContent(0, 0, '╔', nil, s)
-	g.screen.SetContent(0, BannerHeight-1, '╚', nil, s)
+	g.screen.SetContent(0, 0, g.glyphs.topLeft, nil, s)
+	g.screen.SetContent(0, BannerHeight-1, g.glyphs.bottomLeft, nil, s)
 
-	g.screen.SetContent(BannerWidth, 0, '╗', nil, s)
-	g.screen.SetContent(BannerWidth, BannerHeight-1, '╝', nil, s)
+	g.screen.SetContent(BannerWidth, 0, g.glyphs.topRight, nil, s)
+	g.screen.SetContent(BannerWidth, BannerHeight-1, g.glyphs.bottomRight, nil, s)
 	for i := 1; i < BannerHeight-1; i++ {
-		g.screen.SetContent(0, i, '║', nil, s)
-		g.screen.SetContent(BannerWidth, i, '║', nil, s)
+		g.screen.SetContent(0, i, g.glyphs.vertical, nil, s)
+		g.screen.SetContent(BannerWidth, i, g.glyphs.vertical, nil, s)
 	}
 	for i := 0; i < BannerWidth-1; i++ {
-		g.screen.SetContent(i+1, 0, '═', nil, s)
-		g.screen.SetContent(i+1, 3, '═', nil, s)
+		g.screen.SetContent(i+1, 0, g.glyphs.horizontal, nil, s)
+		g.screen.SetContent(i+1, 3, g.glyphs.horizontal, nil, s)
 	}
 	for i, r := range info {
 		g.screen.SetContent(i+BannerPadding, 1, r, nil, s)
 	}
 }
 
-func highlight(symbol rune) rune {
-	switch symbol {
-	case '·':
-		return '⊙'
-	case ' ':
-		return '〇'
-	case '1':
-		return '①'
-	case '2':
-		return '②'
-	case '3':
-		return '③'
-	case '4':
-		return '④'
-	case '5':
-		return '⑤'
-	case '6':
-		return '⑥'
-	case '7':
-		return '⑦'
-	case '8':
-		return '⑧'
-	}
-	return symbol
-}
-
-func getSymbol(board *model.Board, x, y int) (symbol rune) {
-	switch {
-	case board.IsOpened(x, y) && board.IsBlackHole(x, y):
-		return '⨂'
-	case !board.IsOpened(x, y):
-		return '·'
-	default:
-		return toRune(board.GetNeighboursCount(x, y))
-	}
-}
-
 func toRune(i int) rune {
 	if i == 0 {
 		return ' '
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"reflect"
+	"testing"
+)
+
+func TestGame_recordMoves(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want []replay.Action
+	}{
+		{name: "open", keys: []string{"4", "Right", "Space"}, want: []replay.Action{replay.Move, replay.Open}},
+		{name: "flag", keys: []string{"f", "f"}, want: []replay.Action{replay.Flag, replay.Flag}},
+		{name: "flagged cell doesn't open", keys: []string{"f", "Space"}, want: []replay.Action{replay.Flag}},
+		{name: "opened cell doesn't take a flag", keys: []string{"4", "Right", "Space", "f"}, want: []replay.Action{replay.Move, replay.Open}},
+		{name: "chord without flags", keys: []string{"4", "Right", "Space", "c", "Space"}, want: []replay.Action{replay.Move, replay.Open}},
+		{name: "closed cell doesn't chord", keys: []string{"c"}},
+		{name: "undo and redo", keys: []string{"f", "u", "r"}, want: []replay.Action{replay.Flag, replay.Undo, replay.Redo}},
+		{name: "nothing to undo or redo", keys: []string{"u", "r"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the cursor starts on 0,0, 4 steps right is the 1 next to the black hole of the first row
+			g := newLayoutGame(t, jumpLayout)
+			g.replays = t.TempDir()
+			g.startRecording()
+			pressKeys(t, g, tt.keys...)
+			g.stopRecording()
+
+			if got := recorded(t, g.replays); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recorded %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// recorded returns the actions of the latest replay in dir.
+func recorded(t *testing.T, dir string) []replay.Action {
+	t.Helper()
+	path, err := replay.Latest(dir)
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	log, err := replay.Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	var actions []replay.Action
+	for _, e := range log.Events {
+		actions = append(actions, e.Action)
+	}
+	return actions
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"strings"
+)
+
+// GlyphSet chooses the characters the board and the banner are drawn with.
+type GlyphSet string
+
+const (
+	// AutoGlyphs picks Unicode when the terminal can show it, ASCII otherwise.
+	AutoGlyphs    GlyphSet = "auto"
+	UnicodeGlyphs GlyphSet = "unicode"
+	ASCIIGlyphs   GlyphSet = "ascii"
+)
+
+var glyphSets = []GlyphSet{AutoGlyphs, UnicodeGlyphs, ASCIIGlyphs}
+
+type glyphs struct {
+	closed, flag, blackHole rune
+	// highlight replaces the symbol under the cursor, without it the cursor is drawn as [symbol]
+	highlight func(symbol rune) rune
+	// topLeft, topRight, bottomLeft, bottomRight, horizontal and vertical draw the banner frame
+	topLeft, topRight, bottomLeft, bottomRight rune
+	horizontal, vertical                       rune
+	star                                       string
+}
+
+var unicodeGlyphs = glyphs{
+	closed: '·', flag: '⚑', blackHole: '⨂', highlight: highlight,
+	topLeft: '╔', topRight: '╗', bottomLeft: '╚', bottomRight: '╝', horizontal: '═', vertical: '║',
+	star: "★",
+}
+
+var asciiGlyphs = glyphs{
+	closed: '.', flag: 'F', blackHole: '*',
+	topLeft: '+', topRight: '+', bottomLeft: '+', bottomRight: '+', horizontal: '-', vertical: '|',
+	star: "*",
+}
+
+// asciiTerminals are the TERM values of the consoles drawing only a few of the Unicode glyphs.
+var asciiTerminals = []string{"linux", "vt100", "vt102", "vt220", "dumb", "cons25"}
+
+// ParseGlyphSet checks the name of a glyph set, empty is AutoGlyphs.
+func ParseGlyphSet(name string) (GlyphSet, error) {
+	if name == "" {
+		return AutoGlyphs, nil
+	}
+	for _, set := range glyphSets {
+		if string(set) == strings.ToLower(name) {
+			return set, nil
+		}
+	}
+	return "", fmt.Errorf("unknown glyph set %q, want one of %s", name, GlyphSetNames())
+}
+
+func GlyphSetNames() string {
+	names := make([]string, 0, len(glyphSets))
+	for _, set := range glyphSets {
+		names = append(names, string(set))
+	}
+	return strings.Join(names, ", ")
+}
+
+// screenGlyphs resolves AutoGlyphs from the terminal: its character set from the locale, its type
+// and whether tcell can show every Unicode glyph.
+func screenGlyphs(s tcell.Screen, set GlyphSet, term string) glyphs {
+	switch set {
+	case UnicodeGlyphs:
+		return unicodeGlyphs
+	case ASCIIGlyphs:
+		return asciiGlyphs
+	}
+	if !strings.EqualFold(s.CharacterSet(), "UTF-8") {
+		return asciiGlyphs
+	}
+	for _, t := range asciiTerminals {
+		if term == t {
+			return asciiGlyphs
+		}
+	}
+	for _, r := range []rune{'·', '⚑', '⨂', '⊙', '○', '①', '╔', '═', '║', '★'} {
+		if !s.CanDisplay(r, false) {
+			return asciiGlyphs
+		}
+	}
+	return unicodeGlyphs
+}
+
+func (gl glyphs) symbol(board *model.Board, x, y int) rune {
+	switch {
+	case board.IsOpened(x, y) && board.IsBlackHole(x, y):
+		return gl.blackHole
+	case board.IsFlagged(x, y):
+		return gl.flag
+	case !board.IsOpened(x, y):
+		return gl.closed
+	default:
+		return toRune(board.GetNeighboursCount(x, y))
+	}
+}
+
+// highlight is the Unicode cursor, all the glyphs are single width.
+func highlight(symbol rune) rune {
+	switch symbol {
+	case '·':
+		return '⊙'
+	case ' ':
+		return '○'
+	case '1':
+		return '①'
+	case '2':
+		return '②'
+	case '3':
+		return '③'
+	case '4':
+		return '④'
+	case '5':
+		return '⑤'
+	case '6':
+		return '⑥'
+	case '7':
+		return '⑦'
+	case '8':
+		return '⑧'
+	}
+	return symbol
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestParseGlyphSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    GlyphSet
+		wantErr bool
+	}{
+		{name: "", want: AutoGlyphs},
+		{name: "auto", want: AutoGlyphs},
+		{name: "Unicode", want: UnicodeGlyphs},
+		{name: "ASCII", want: ASCIIGlyphs},
+		{name: "emoji", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGlyphSet(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGlyphSet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseGlyphSet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScreenGlyphs(t *testing.T) {
+	tests := []struct {
+		name        string
+		screen      fakeScreen
+		set         GlyphSet
+		term        string
+		wantUnicode bool
+	}{
+		{name: "unicode terminal", screen: fakeScreen{charset: "UTF-8"}, set: AutoGlyphs, term: "xterm-256color", wantUnicode: true},
+		{name: "lower case charset", screen: fakeScreen{charset: "utf-8"}, set: AutoGlyphs, term: "xterm", wantUnicode: true},
+		{name: "latin-1 locale", screen: fakeScreen{charset: "ISO8859-1"}, set: AutoGlyphs, term: "xterm"},
+		{name: "linux console", screen: fakeScreen{charset: "UTF-8"}, set: AutoGlyphs, term: "linux"},
+		{name: "vt100", screen: fakeScreen{charset: "UTF-8"}, set: AutoGlyphs, term: "vt100"},
+		{name: "missing flag glyph", screen: fakeScreen{charset: "UTF-8", missing: []rune{'⚑'}}, set: AutoGlyphs, term: "xterm"},
+		{name: "missing circled number", screen: fakeScreen{charset: "UTF-8", missing: []rune{'①'}}, set: AutoGlyphs, term: "xterm"},
+		{name: "forced unicode", screen: fakeScreen{charset: "ISO8859-1"}, set: UnicodeGlyphs, term: "linux", wantUnicode: true},
+		{name: "forced ascii", screen: fakeScreen{charset: "UTF-8"}, set: ASCIIGlyphs, term: "xterm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := screenGlyphs(tt.screen, tt.set, tt.term)
+			if unicode := got.flag == unicodeGlyphs.flag; unicode != tt.wantUnicode {
+				t.Errorf("screenGlyphs() flag = %q, want unicode %v", got.flag, tt.wantUnicode)
+			}
+		})
+	}
+}
+
+func TestGlyphs_symbol(t *testing.T) {
+	g := newLayoutGame(t, jumpLayout)
+	g.board.Open(4, 0)
+	g.board.ToggleFlag(5, 0)
+	tests := []struct {
+		name        string
+		x, y        int
+		wantASCII   rune
+		wantUnicode rune
+	}{
+		{name: "closed", x: 0, y: 0, wantASCII: '.', wantUnicode: '·'},
+		{name: "number", x: 4, y: 0, wantASCII: '1', wantUnicode: '1'},
+		{name: "flag", x: 5, y: 0, wantASCII: 'F', wantUnicode: '⚑'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := asciiGlyphs.symbol(&g.board, tt.x, tt.y); got != tt.wantASCII {
+				t.Errorf("ascii symbol() = %q, want %q", got, tt.wantASCII)
+			}
+			if got := unicodeGlyphs.symbol(&g.board, tt.x, tt.y); got != tt.wantUnicode {
+				t.Errorf("unicode symbol() = %q, want %q", got, tt.wantUnicode)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"strconv"
+	"strings"
+)
+
+// maxCount keeps the count prefix within what any board needs.
+const maxCount = 999
+
+// handleCount adds a digit not bound to any action to the count prefix of the next action.
+func (g *Game) handleCount(event *tcell.EventKey) {
+	r := event.Rune()
+	if event.Key() != tcell.KeyRune || r < '0' || r > '9' || (r == '0' && g.count == 0) {
+		return
+	}
+	g.count = g.count*10 + int(r-'0')
+	if g.count > maxCount {
+		g.count = maxCount
+	}
+}
+
+// repeat returns how many times the next action runs and resets the count prefix and the jump.
+func (g *Game) repeat() int {
+	n := g.count
+	if g.jump {
+		n = g.board.GetSize()
+	}
+	g.count, g.jump = 0, false
+	if n == 0 {
+		return 1
+	}
+	return n
+}
+
+// moveCursor moves the cursor by the step n times, every axis stops at the board boundaries on its own,
+// so a long diagonal move ends in a corner.
+func (g *Game) moveCursor(step point, n int) {
+	x, y := g.cursorCell()
+	g.setCursorCell(clamp(x+step.x*n, g.board.GetSize()), clamp(y+step.y*n, g.board.GetSize()))
+}
+
+func (g *Game) setCursorCell(x, y int) {
+	g.cursor = point{x: g.location.x + x*XAxisStep, y: g.location.y + y*YAxisStep}
+}
+
+func clamp(v, size int) int {
+	if v < 0 {
+		return 0
+	}
+	if v >= size {
+		return size - 1
+	}
+	return v
+}
+
+// nextCell moves the cursor n times to the next cell in the reading order that matches, wrapping at the end.
+func (g *Game) nextCell(n int, match func(x, y int) bool) {
+	size := g.board.GetSize()
+	x, y := g.cursorCell()
+	i := y*size + x
+	for ; n > 0; n-- {
+		found := false
+		for step := 1; step <= size*size; step++ {
+			j := (i + step) % (size * size)
+			if match(j%size, j/size) {
+				i, found = j, true
+				break
+			}
+		}
+		if !found {
+			break
+		}
+	}
+	g.setCursorCell(i%size, i/size)
+}
+
+func (g *Game) isClosed(x, y int) bool {
+	return !g.board.IsOpened(x, y) && !g.board.IsFlagged(x, y)
+}
+
+// isUnresolved tells if the cell is an opened number with closed cells around it.
+func (g *Game) isUnresolved(x, y int) bool {
+	if !g.board.IsOpened(x, y) || g.board.IsBlackHole(x, y) || g.board.GetNeighboursCount(x, y) == 0 {
+		return false
+	}
+	size := g.board.GetSize()
+	for nx := x - 1; nx <= x+1; nx++ {
+		for ny := y - 1; ny <= y+1; ny++ {
+			if nx >= 0 && nx < size && ny >= 0 && ny < size && g.isClosed(nx, ny) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handlePrompt edits the go to prompt. Enter moves the cursor, Esc closes the prompt.
+func (g *Game) handlePrompt(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyEscape:
+		g.prompting = false
+	case tcell.KeyEnter:
+		x, y, err := parseCell(g.prompt, g.board.GetSize())
+		if err != nil {
+			g.promptError = err.Error()
+			return
+		}
+		g.prompting = false
+		g.setCursorCell(x, y)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(g.prompt) > 0 {
+			g.prompt = g.prompt[:len(g.prompt)-1]
+		}
+		g.promptError = ""
+	case tcell.KeyRune:
+		if r := event.Rune(); (r >= '0' && r <= '9') || r == ',' || r == ' ' {
+			g.prompt += string(r)
+			g.promptError = ""
+		}
+	}
+}
+
+func (g *Game) openPrompt() {
+	g.prompting = true
+	g.prompt, g.promptError = "", ""
+}
+
+// parseCell reads the 1-based column and row, i.e. "3,12" or "3 12", into the cell coordinates.
+func parseCell(s string, size int) (x, y int, err error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("want column,row")
+	}
+	column, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("want column,row")
+	}
+	row, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("want column,row")
+	}
+	if column < 1 || column > size || row < 1 || row > size {
+		return 0, 0, fmt.Errorf("out of the board")
+	}
+	return column - 1, row - 1, nil
+}
+
+// boardHelp is the banner of the board view, it shows the pending count, jump or prompt instead of the keys.
+func (g *Game) boardHelp() string {
+	switch {
+	case g.notice != "":
+		return g.notice
+	case g.prompting && g.promptError != "":
+		return fmt.Sprintf("go to column,row 1-%d: %s_ (%s)", g.board.GetSize(), g.prompt, g.promptError)
+	case g.prompting:
+		return fmt.Sprintf("go to column,row 1-%d: %s_", g.board.GetSize(), g.prompt)
+	case g.jump:
+		return "jump: " + g.keys.help("move:to the edge, diagonals to a corner")
+	case g.count > 0:
+		return fmt.Sprintf("%d: repeat the next move", g.count)
+	}
+	return g.keys.help("move:move", "open:open/chord", "flag:flag", "menu:menu", "quit:quit")
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"testing"
+)
+
+const jumpLayout = `
+	0 0 0 0 1 *
+	0 0 0 0 1 1
+	0 0 0 0 0 0
+	0 0 0 0 0 0
+	1 1 0 0 0 0
+	* 1 0 0 0 0
+	`
+
+// pressKeys sends the keys named like in keys.json to the game.
+func pressKeys(t *testing.T, g *Game, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		k, err := parseKey(name)
+		if err != nil {
+			t.Fatalf("parseKey() error = %v", err)
+		}
+		g.handleEventKey(tcell.NewEventKey(k.key, k.rune, tcell.ModNone))
+	}
+}
+
+func TestGame_countAndJump(t *testing.T) {
+	tests := []struct {
+		name   string
+		opened [][]int
+		keys   []string
+		wantX  int
+		wantY  int
+	}{
+		{name: "move", keys: []string{"Right", "Down"}, wantX: 1, wantY: 1},
+		{name: "count repeats the move", keys: []string{"3", "Right"}, wantX: 3},
+		{name: "count of several digits", keys: []string{"1", "0", "Down"}, wantY: 5},
+		{name: "count stops at the edge", keys: []string{"9", "9", "PgDn"}, wantX: 5, wantY: 5},
+		{name: "count is used once", keys: []string{"3", "Right", "Right"}, wantX: 4},
+		{name: "leading zero is no count", keys: []string{"0", "Right"}, wantX: 1},
+		{name: "jump to the edge", keys: []string{"g", "Down"}, wantY: 5},
+		{name: "jump to a corner", keys: []string{"Down", "g", "PgDn"}, wantX: 5, wantY: 5},
+		{name: "jump is used once", keys: []string{"g", "Right", "Down"}, wantX: 5, wantY: 1},
+		{name: "next closed cell skips the flags", keys: []string{"f", "Tab"}, wantX: 1},
+		{name: "count of next closed cells", keys: []string{"2", "Tab"}, wantX: 2},
+		{name: "next closed cell wraps", keys: []string{"f", "g", "PgDn", "Tab"}, wantX: 1},
+		{name: "next number", opened: [][]int{{4, 0}, {4, 1}, {1, 4}}, keys: []string{"."}, wantX: 4},
+		{name: "count of next numbers", opened: [][]int{{4, 0}, {4, 1}, {1, 4}}, keys: []string{"3", "."}, wantX: 1, wantY: 4},
+		{name: "count is dropped by a paused game", keys: []string{"p", "3", "p", "Right"}, wantX: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newLayoutGame(t, jumpLayout)
+			for _, p := range tt.opened {
+				g.board.Open(p[0], p[1])
+			}
+			pressKeys(t, g, tt.keys...)
+			if x, y := g.cursorCell(); x != tt.wantX || y != tt.wantY {
+				t.Errorf("cursorCell() = %d,%d, want %d,%d", x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestGame_handlePrompt(t *testing.T) {
+	tests := []struct {
+		name          string
+		keys          []string
+		wantX         int
+		wantY         int
+		wantPrompting bool
+		wantError     bool
+	}{
+		{name: "go to", keys: []string{":", "3", ",", "6", "Enter"}, wantX: 2, wantY: 5},
+		{name: "space separated", keys: []string{":", "6", "Space", "1", "Enter"}, wantX: 5},
+		{name: "backspace", keys: []string{":", "3", "4", "Backspace", ",", "2", "Enter"}, wantX: 2, wantY: 1},
+		{name: "moves aren't typed", keys: []string{":", "Right", "2", "f", ",", "2", "Enter"}, wantX: 1, wantY: 1},
+		{name: "escape", keys: []string{":", "3", ",", "3", "Esc"}},
+		{name: "out of the board", keys: []string{":", "7", ",", "1", "Enter"}, wantPrompting: true, wantError: true},
+		{name: "missing row", keys: []string{":", "3", "Enter"}, wantPrompting: true, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newLayoutGame(t, jumpLayout)
+			pressKeys(t, g, tt.keys...)
+			if x, y := g.cursorCell(); x != tt.wantX || y != tt.wantY {
+				t.Errorf("cursorCell() = %d,%d, want %d,%d", x, y, tt.wantX, tt.wantY)
+			}
+			if g.prompting != tt.wantPrompting || (g.promptError != "") != tt.wantError {
+				t.Errorf("prompting = %v, promptError = %q, want %v and an error %v", g.prompting, g.promptError, tt.wantPrompting, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestParseCell(t *testing.T) {
+	tests := []struct {
+		s       string
+		wantX   int
+		wantY   int
+		wantErr bool
+	}{
+		{s: "1,1"},
+		{s: "3,12", wantX: 2, wantY: 11},
+		{s: "3 12", wantX: 2, wantY: 11},
+		{s: " 16 , 16 ", wantX: 15, wantY: 15},
+		{s: "17,1", wantErr: true},
+		{s: "0,1", wantErr: true},
+		{s: "3", wantErr: true},
+		{s: "3,4,5", wantErr: true},
+		{s: "a,1", wantErr: true},
+		{s: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			x, y, err := parseCell(tt.s, 16)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCell() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if x != tt.wantX || y != tt.wantY {
+				t.Errorf("parseCell() = %d,%d, want %d,%d", x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func newLayoutGame(t *testing.T, layout string) *Game {
+	t.Helper()
+	board, err := model.ParseLayout(layout)
+	if err != nil {
+		t.Fatalf("ParseLayout() error = %v", err)
+	}
+	g, err := newGame(Config{Difficulty: model.Difficulty{Size: board.GetSize(), BlackHolesCount: board.GetBlackHolesCount()}}, board, nil)
+	if err != nil {
+		t.Fatalf("newGame() error = %v", err)
+	}
+	return g
+}
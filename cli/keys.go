@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"os"
+	"sort"
+	"strings"
+)
+
+// KeysFileName is the key bindings config file in the game config directory.
+const KeysFileName = "keys.json"
+
+type Action string
+
+const (
+	MoveUp        Action = "up"
+	MoveDown      Action = "down"
+	MoveLeft      Action = "left"
+	MoveRight     Action = "right"
+	MoveUpLeft    Action = "up-left"
+	MoveUpRight   Action = "up-right"
+	MoveDownLeft  Action = "down-left"
+	MoveDownRight Action = "down-right"
+	// Open opens a closed cell and chords an opened one.
+	Open    Action = "open"
+	Flag    Action = "flag"
+	Chord   Action = "chord"
+	Hint    Action = "hint"
+	Pause   Action = "pause"
+	Restart Action = "restart"
+	Undo    Action = "undo"
+	Redo    Action = "redo"
+	Menu    Action = "menu"
+	Scores  Action = "scores"
+	Stats   Action = "stats"
+	Quit    Action = "quit"
+	// Jump makes the next move go as far as the board allows, i.e. to an edge or a corner.
+	Jump Action = "jump"
+	// NextClosed moves to the next closed cell not flagged, in the reading order.
+	NextClosed Action = "next-closed"
+	// NextNumber moves to the next opened number with closed cells around it.
+	NextNumber Action = "next-number"
+	Goto       Action = "goto"
+	// Theme switches to the next colour theme.
+	NextTheme Action = "theme"
+)
+
+var actions = []Action{MoveUp, MoveDown, MoveLeft, MoveRight, MoveUpLeft, MoveUpRight, MoveDownLeft, MoveDownRight,
+	Open, Flag, Chord, Hint, Pause, Restart, Undo, Redo, Menu, Scores, Stats, Quit, Jump, NextClosed, NextNumber, Goto, NextTheme}
+
+// moves are the cursor steps of the move actions.
+var moves = map[Action]point{
+	MoveUp: {0, -1}, MoveDown: {0, 1}, MoveLeft: {-1, 0}, MoveRight: {1, 0},
+	MoveUpLeft: {-1, -1}, MoveUpRight: {1, -1}, MoveDownLeft: {-1, 1}, MoveDownRight: {1, 1},
+}
+
+// key is a tcell key, rune is only set for tcell.KeyRune.
+type key struct {
+	key  tcell.Key
+	rune rune
+}
+
+func eventKey(event *tcell.EventKey) key {
+	if event.Key() == tcell.KeyRune {
+		return key{key: tcell.KeyRune, rune: event.Rune()}
+	}
+	return key{key: event.Key()}
+}
+
+var keysByName = func() map[string]tcell.Key {
+	m := map[string]tcell.Key{}
+	for k, name := range tcell.KeyNames {
+		m[strings.ToLower(name)] = k
+	}
+	return m
+}()
+
+// parseKey reads a single character or a tcell key name like Up, PgDn, Enter or Ctrl-C.
+func parseKey(name string) (key, error) {
+	if r := []rune(name); len(r) == 1 {
+		return key{key: tcell.KeyRune, rune: r[0]}, nil
+	}
+	if strings.EqualFold(name, "space") {
+		return key{key: tcell.KeyRune, rune: ' '}, nil
+	}
+	if k, ok := keysByName[strings.ToLower(name)]; ok {
+		return key{key: k}, nil
+	}
+	return key{}, fmt.Errorf("unknown key %q", name)
+}
+
+func (k key) String() string {
+	switch {
+	case k.key == tcell.KeyRune && k.rune == ' ':
+		return "space"
+	case k.key == tcell.KeyRune:
+		return string(k.rune)
+	}
+	return strings.ToLower(tcell.KeyNames[k.key])
+}
+
+// Bindings maps the keys to the actions, an action can have several keys.
+type Bindings struct {
+	actions map[key]Action
+	keys    map[Action][]key
+	// moveLabel describes the four main moves in the help text.
+	moveLabel string
+}
+
+type preset struct {
+	moveLabel string
+	keys      map[Action][]string
+}
+
+var presets = map[string]preset{
+	"arrows": {
+		moveLabel: "arrows",
+		keys: map[Action][]string{
+			MoveUp: {"Up"}, MoveDown: {"Down"}, MoveLeft: {"Left"}, MoveRight: {"Right"},
+			MoveUpLeft: {"Home"}, MoveUpRight: {"PgUp"}, MoveDownLeft: {"End"}, MoveDownRight: {"PgDn"},
+			Open: {"Space"}, Flag: {"f"}, Chord: {"c"}, Hint: {"?"}, Pause: {"p"}, Restart: {"n"},
+			Undo: {"u"}, Redo: {"r"}, Menu: {"m"}, Scores: {"h"}, Stats: {"s"}, Quit: {"Esc", "Ctrl-C"},
+			Jump: {"g"}, NextClosed: {"Tab"}, NextNumber: {"."}, Goto: {":"}, NextTheme: {"T"},
+		},
+	},
+	"vim": {
+		moveLabel: "hjkl",
+		keys: map[Action][]string{
+			MoveUp: {"k"}, MoveDown: {"j"}, MoveLeft: {"h"}, MoveRight: {"l"},
+			MoveUpLeft: {"y"}, MoveUpRight: {"u"}, MoveDownLeft: {"b"}, MoveDownRight: {"n"},
+			Open: {"Space"}, Flag: {"f"}, Chord: {"c"}, Hint: {"?"}, Pause: {"p"}, Restart: {"N"},
+			Undo: {"U"}, Redo: {"R"}, Menu: {"m"}, Scores: {"H"}, Stats: {"S"}, Quit: {"Esc", "q", "Ctrl-C"},
+			Jump: {"g"}, NextClosed: {"Tab", "w"}, NextNumber: {".", "e"}, Goto: {":"}, NextTheme: {"T"},
+		},
+	},
+	"wasd": {
+		moveLabel: "wasd",
+		keys: map[Action][]string{
+			MoveUp: {"w"}, MoveDown: {"s"}, MoveLeft: {"a"}, MoveRight: {"d"},
+			MoveUpLeft: {"q"}, MoveUpRight: {"e"}, MoveDownLeft: {"z"}, MoveDownRight: {"c"},
+			Open: {"Space"}, Flag: {"f"}, Chord: {"x"}, Hint: {"?"}, Pause: {"p"}, Restart: {"n"},
+			Undo: {"u"}, Redo: {"r"}, Menu: {"m"}, Scores: {"h"}, Stats: {"t"}, Quit: {"Esc", "Ctrl-C"},
+			Jump: {"g"}, NextClosed: {"Tab"}, NextNumber: {"."}, Goto: {":"}, NextTheme: {"T"},
+		},
+	},
+}
+
+const DefaultPreset = "arrows"
+
+// Presets returns the names of the built-in key binding presets.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewBindings returns the bindings of the preset with the overrides applied. A key given to an action
+// in the overrides is taken from any other action.
+func NewBindings(name string, overrides map[Action][]string) (Bindings, error) {
+	p, ok := presets[name]
+	if !ok {
+		return Bindings{}, fmt.Errorf("unknown key bindings preset %q, want one of %s", name, strings.Join(Presets(), ", "))
+	}
+	b := Bindings{actions: map[key]Action{}, keys: map[Action][]key{}, moveLabel: p.moveLabel}
+	for _, a := range actions {
+		if err := b.bind(a, p.keys[a]); err != nil {
+			return Bindings{}, err
+		}
+	}
+	for a := range overrides {
+		if !validAction(a) {
+			return Bindings{}, fmt.Errorf("unknown action %q", a)
+		}
+	}
+	for _, a := range actions {
+		names, ok := overrides[a]
+		if !ok {
+			continue
+		}
+		if a == MoveUp || a == MoveDown || a == MoveLeft || a == MoveRight {
+			b.moveLabel = ""
+		}
+		for _, k := range b.keys[a] {
+			delete(b.actions, k)
+		}
+		b.keys[a] = nil
+		if err := b.bind(a, names); err != nil {
+			return Bindings{}, err
+		}
+	}
+	return b, nil
+}
+
+func (b *Bindings) bind(a Action, names []string) error {
+	for _, name := range names {
+		k, err := parseKey(name)
+		if err != nil {
+			return err
+		}
+		if old, ok := b.actions[k]; ok {
+			b.keys[old] = removeKey(b.keys[old], k)
+		}
+		b.actions[k] = a
+		b.keys[a] = append(b.keys[a], k)
+	}
+	return nil
+}
+
+func removeKey(keys []key, k key) []key {
+	var rest []key
+	for _, other := range keys {
+		if other != k {
+			rest = append(rest, other)
+		}
+	}
+	return rest
+}
+
+func validAction(a Action) bool {
+	for _, known := range actions {
+		if known == a {
+			return true
+		}
+	}
+	return false
+}
+
+// action returns the action bound to the key of the event, ok is false for unbound keys.
+func (b Bindings) action(event *tcell.EventKey) (Action, bool) {
+	a, ok := b.actions[eventKey(event)]
+	return a, ok
+}
+
+// key returns the name of the first key of the action, "none" if it has no keys.
+func (b Bindings) key(a Action) string {
+	if len(b.keys[a]) == 0 {
+		return "none"
+	}
+	return b.keys[a][0].String()
+}
+
+// help describes the actions in the banner, i.e. "arrows: move, space: open/chord".
+func (b Bindings) help(entries ...string) string {
+	parts := make([]string, 0, len(entries))
+	for _, e := range entries {
+		a, description, _ := strings.Cut(e, ":")
+		label := b.key(Action(a))
+		if a == "move" {
+			label = b.moveLabel
+			if label == "" {
+				label = strings.Join([]string{b.key(MoveUp), b.key(MoveLeft), b.key(MoveDown), b.key(MoveRight)}, "/")
+			}
+		}
+		parts = append(parts, label+": "+description)
+	}
+	return strings.Join(parts, ", ")
+}
+
+type keysFile struct {
+	Preset   string              `json:"preset"`
+	Bindings map[Action][]string `json:"bindings"`
+}
+
+// LoadBindings reads the bindings from the config file, a missing file gives the default preset.
+// A non-empty preset overrides the one of the file.
+func LoadBindings(path, preset string) (Bindings, error) {
+	f := keysFile{Preset: DefaultPreset}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return Bindings{}, err
+	default:
+		if err := json.Unmarshal(data, &f); err != nil {
+			return Bindings{}, fmt.Errorf("can't read key bindings from %s: %w", path, err)
+		}
+	}
+	if preset != "" {
+		f.Preset = preset
+	}
+	if f.Preset == "" {
+		f.Preset = DefaultPreset
+	}
+	return NewBindings(f.Preset, f.Bindings)
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    key
+		wantErr bool
+	}{
+		{name: "f", want: key{key: tcell.KeyRune, rune: 'f'}},
+		{name: "N", want: key{key: tcell.KeyRune, rune: 'N'}},
+		{name: "?", want: key{key: tcell.KeyRune, rune: '?'}},
+		{name: "Space", want: key{key: tcell.KeyRune, rune: ' '}},
+		{name: "space", want: key{key: tcell.KeyRune, rune: ' '}},
+		{name: "Up", want: key{key: tcell.KeyUp}},
+		{name: "pgdn", want: key{key: tcell.KeyPgDn}},
+		{name: "Enter", want: key{key: tcell.KeyEnter}},
+		{name: "Ctrl-F", want: key{key: tcell.KeyCtrlF}},
+		{name: "Esc", want: key{key: tcell.KeyEscape}},
+		{name: "", wantErr: true},
+		{name: "Hyper-X", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKey(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseKey() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBindings(t *testing.T) {
+	tests := []struct {
+		name      string
+		preset    string
+		overrides map[Action][]string
+		event     *tcell.EventKey
+		want      Action
+		wantBound bool
+		wantHelp  string
+		wantErr   bool
+	}{
+		{name: "arrows", preset: "arrows", event: tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone), want: MoveLeft, wantBound: true,
+			wantHelp: "arrows: move, space: open/chord"},
+		{name: "vim", preset: "vim", event: runeKey('l'), want: MoveRight, wantBound: true, wantHelp: "hjkl: move, space: open/chord"},
+		{name: "wasd", preset: "wasd", event: runeKey('x'), want: Chord, wantBound: true, wantHelp: "wasd: move, space: open/chord"},
+		{name: "unbound", preset: "arrows", event: runeKey('Z')},
+		{
+			name: "override takes the key of another action", preset: "vim", overrides: map[Action][]string{MoveLeft: {"a"}, Open: {"l", "Enter"}},
+			event: runeKey('l'), want: Open, wantBound: true, wantHelp: "k/a/j/none: move, l: open/chord",
+		},
+		{name: "unknown preset", preset: "emacs", wantErr: true},
+		{name: "unknown action", preset: "arrows", overrides: map[Action][]string{"fly": {"x"}}, wantErr: true},
+		{name: "unknown key", preset: "arrows", overrides: map[Action][]string{Open: {"Hyper-X"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewBindings(tt.preset, tt.overrides)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewBindings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if a, bound := b.action(tt.event); a != tt.want || bound != tt.wantBound {
+				t.Errorf("action() = %q, %v, want %q, %v", a, bound, tt.want, tt.wantBound)
+			}
+			if tt.wantHelp != "" {
+				if got := b.help("move:move", "open:open/chord"); got != tt.wantHelp {
+					t.Errorf("help() = %q, want %q", got, tt.wantHelp)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadBindings(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		preset  string
+		event   *tcell.EventKey
+		want    Action
+		wantErr bool
+	}{
+		{name: "no file", event: tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone), want: MoveUp},
+		{name: "preset of the file", file: `{"preset": "vim"}`, event: runeKey('k'), want: MoveUp},
+		{name: "preset overrides the file", file: `{"preset": "vim"}`, preset: "wasd", event: runeKey('w'), want: MoveUp},
+		{name: "bindings of the file", file: `{"bindings": {"flag": ["Enter"]}}`, event: tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), want: Flag},
+		{name: "empty preset", file: `{"preset": ""}`, event: runeKey('f'), want: Flag},
+		{name: "not json", file: `preset: vim`, wantErr: true},
+		{name: "unknown preset", file: `{"preset": "emacs"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), KeysFileName)
+			if tt.file != "" {
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatalf("WriteFile() error = %v", err)
+				}
+			}
+			b, err := LoadBindings(path, tt.preset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadBindings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if a, _ := b.action(tt.event); a != tt.want {
+				t.Errorf("action() = %q, want %q", a, tt.want)
+			}
+		})
+	}
+}
+
+func TestGame_restartNeedsConfirmation(t *testing.T) {
+	board, err := model.ParseLayout(`
+		* 1 0 0
+		1 1 0 0
+		1 1 1 1
+		* 1 1 *
+		`)
+	if err != nil {
+		t.Fatalf("ParseLayout() error = %v", err)
+	}
+	g, err := newGame(Config{Difficulty: model.Easy}, board, nil)
+	if err != nil {
+		t.Fatalf("newGame() error = %v", err)
+	}
+	g.board.Open(3, 0)
+	n := runeKey('n')
+
+	g.handleEventKey(n)
+	if g.board.GetMoves() != 1 || g.notice == "" {
+		t.Fatalf("first restart key: moves = %d, notice = %q, want the game kept and a notice", g.board.GetMoves(), g.notice)
+	}
+	g.handleEventKey(runeKey('f'))
+	g.handleEventKey(n)
+	if g.board.GetMoves() != 1 {
+		t.Fatalf("restart key after another key: moves = %d, want the game kept", g.board.GetMoves())
+	}
+	g.handleEventKey(n)
+	if g.board.GetMoves() != 0 || g.board.GetSize() != model.Easy.Size {
+		t.Errorf("confirmed restart: moves = %d, size = %d, want a new easy board", g.board.GetMoves(), g.board.GetSize())
+	}
+}
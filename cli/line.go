@@ -0,0 +1,439 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxListedCells is how many opened cells are read out one by one, bigger openings are only counted.
+const maxListedCells = 8
+
+const lineHelp = `Cells are a column letter and a row number, i.e. c5.
+open c5, flag c5, chord c5: play the cell, o, f and c for short
+describe c5: what the cell shows, describe around c5: the cells around it
+row 5: the cells of the row
+status: the time, the moves and the flags
+hint: a cell proven safe, the game becomes a practice game
+undo, redo: take back the last move and put it back
+new [easy|medium|hard|daily]: start another board
+code: the board code to share
+quit: leave the game`
+
+// LineGame is the front end for screen readers. It reads commands line by line and answers
+// in plain sentences describing what changed, nothing is drawn.
+type LineGame struct {
+	game        *Game
+	in          io.Reader
+	out         io.Writer
+	events      []model.Event
+	unsubscribe func()
+}
+
+func NewLineGame(cfg Config, in io.Reader, out io.Writer) (*LineGame, error) {
+	board, err := configBoard(cfg)
+	if err != nil {
+		return nil, err
+	}
+	g, err := newGame(cfg, board, nil)
+	if err != nil {
+		return nil, err
+	}
+	l := &LineGame{game: g, in: in, out: out}
+	l.listen()
+	return l, nil
+}
+
+// listen collects the events of the current board, they are described after every command.
+func (l *LineGame) listen() {
+	if l.unsubscribe != nil {
+		l.unsubscribe()
+	}
+	l.unsubscribe = l.game.board.Subscribe(func(e model.Event) { l.events = append(l.events, e) })
+}
+
+// Start reads the commands until quit or the end of the input.
+func (l *LineGame) Start() error {
+	fmt.Fprintln(l.out, l.intro())
+	l.say("Type help for the commands.")
+	sc := bufio.NewScanner(l.in)
+	for {
+		fmt.Fprint(l.out, "> ")
+		if !sc.Scan() || !l.handle(sc.Text()) {
+			break
+		}
+	}
+	// there's no resuming in the line mode
+	l.game.forfeitDaily()
+	l.game.stopRecording()
+	return sc.Err()
+}
+
+// say answers the player, text that's already formatted is written with fmt.Fprintln.
+func (l *LineGame) say(format string, args ...any) {
+	fmt.Fprintf(l.out, format+"\n", args...)
+}
+
+func (l *LineGame) intro() string {
+	b := &l.game.board
+	last := columnName(b.GetSize() - 1)
+	return fmt.Sprintf("New %s board, %d by %d with %d black holes. Columns a to %s, rows 1 to %d.",
+		l.game.difficulty.Name, b.GetSize(), b.GetSize(), b.GetBlackHolesCount(), last, b.GetSize())
+}
+
+// handle runs the command, it returns false when the player quits.
+func (l *LineGame) handle(line string) bool {
+	fields := strings.Fields(strings.ToLower(line))
+	if len(fields) == 0 {
+		return true
+	}
+	command, args := fields[0], fields[1:]
+	l.events = nil
+	switch command {
+	case "help", "?":
+		fmt.Fprintln(l.out, lineHelp)
+	case "open", "o", "flag", "f", "chord", "c":
+		l.play(command[:1], args)
+	case "describe", "d":
+		l.describe(args)
+	case "row", "r":
+		l.row(args)
+	case "status", "s":
+		fmt.Fprintln(l.out, l.status())
+	case "hint":
+		l.hint()
+	case "undo", "redo":
+		l.undo(command == "undo")
+	case "new":
+		l.newBoard(args)
+	case "code":
+		if code, ok := l.game.board.Code(); ok {
+			l.say("Board code %s", code)
+		} else {
+			l.say("This board has no code.")
+		}
+	case "quit", "q", "exit":
+		return false
+	default:
+		l.say("Unknown command %s, type help for the commands.", command)
+	}
+	return true
+}
+
+func (l *LineGame) play(command string, args []string) {
+	x, y, ok := l.cell(args)
+	if !ok {
+		return
+	}
+	b := &l.game.board
+	if b.GetState() != model.InProgress {
+		l.say("The game is over, type new for another board.")
+		return
+	}
+	var action replay.Action
+	switch command {
+	case "o":
+		b.Open(x, y)
+		action = replay.Open
+	case "f":
+		b.ToggleFlag(x, y)
+		action = replay.Flag
+	case "c":
+		b.Chord(x, y)
+		action = replay.Chord
+	}
+	if len(l.events) == 0 {
+		l.say("Nothing changed, %s is %s.", cellName(x, y), l.cellText(x, y))
+		return
+	}
+	l.game.record(action, x, y)
+	l.sayEvents()
+}
+
+// sayEvents describes the changes made by the last command.
+func (l *LineGame) sayEvents() {
+	b := &l.game.board
+	for _, e := range l.events {
+		switch e := e.(type) {
+		case model.CellsOpened:
+			if opened := l.opened(e); opened != "" {
+				fmt.Fprintln(l.out, opened)
+			}
+		case model.CellsClosed:
+			l.say("Closed %s.", cellsCount(len(e.Cells)))
+		case model.CellFlagged:
+			verb := "Unflagged"
+			if e.Flagged {
+				verb = "Flagged"
+			}
+			l.say("%s %s, %d of %d flags.", verb, cellName(e.X, e.Y), b.GetFlagsCount(), b.GetBlackHolesCount())
+		case model.StateChanged:
+			l.sayState(e)
+		}
+	}
+}
+
+// opened describes the opened cells, one by one if there are only a few.
+func (l *LineGame) opened(e model.CellsOpened) string {
+	b := &l.game.board
+	if len(e.Cells) > maxListedCells {
+		return fmt.Sprintf("Opened %s; %s is %s.", cellsCount(len(e.Cells)), cellName(e.X, e.Y), l.cellText(e.X, e.Y))
+	}
+	described := make([]string, 0, len(e.Cells))
+	for _, p := range e.Cells {
+		if b.IsBlackHole(p.X(), p.Y()) {
+			continue
+		}
+		described = append(described, fmt.Sprintf("%s is %s", cellName(p.X(), p.Y()), l.cellText(p.X(), p.Y())))
+	}
+	if len(described) == 0 {
+		// the black hole is told by the lost game
+		return ""
+	}
+	return fmt.Sprintf("Opened %s; %s.", cellsCount(len(described)), strings.Join(described, ", "))
+}
+
+func (l *LineGame) sayState(e model.StateChanged) {
+	g := l.game
+	b := &g.board
+	switch e.To {
+	case model.Lost:
+		x, y, _ := b.GetLostAt()
+		l.say("%s is a black hole, you lost after %d moves.", cellName(x, y), b.GetMoves())
+	case model.Won:
+		l.say("You won in %.2f seconds and %d moves!", b.GetElapsed().Seconds(), b.GetMoves())
+	case model.InProgress:
+		l.say("The game goes on.")
+		return
+	}
+	switch {
+	case b.IsPractice():
+		l.say("Practice game, it doesn't count for high scores.")
+	case g.scoreError != nil:
+		l.say("Can't save the score: %v", g.scoreError)
+	case g.result != nil && g.result.PersonalBest:
+		l.say("New personal best, #%d in %s high scores.", g.result.Rank, g.scoresKey())
+	case g.result != nil && g.result.Rank > 0:
+		l.say("#%d in %s high scores.", g.result.Rank, g.scoresKey())
+	}
+	l.say("Type new for another board.")
+}
+
+func (l *LineGame) describe(args []string) {
+	if len(args) > 0 && args[0] == "around" {
+		x, y, ok := l.cell(args[1:])
+		if !ok {
+			return
+		}
+		var around []string
+		size := l.game.board.GetSize()
+		for ny := y - 1; ny <= y+1; ny++ {
+			for nx := x - 1; nx <= x+1; nx++ {
+				if (nx != x || ny != y) && nx >= 0 && nx < size && ny >= 0 && ny < size {
+					around = append(around, cellName(nx, ny)+" "+l.cellText(nx, ny))
+				}
+			}
+		}
+		l.say("Around %s: %s.", cellName(x, y), strings.Join(around, ", "))
+		return
+	}
+	x, y, ok := l.cell(args)
+	if !ok {
+		return
+	}
+	l.say("%s is %s.", cellName(x, y), l.cellText(x, y))
+}
+
+func (l *LineGame) row(args []string) {
+	size := l.game.board.GetSize()
+	if len(args) != 1 {
+		l.say("Which row? I.e. row 5.")
+		return
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > size {
+		l.say("Rows go from 1 to %d.", size)
+		return
+	}
+	cells := make([]string, 0, size)
+	for x := 0; x < size; x++ {
+		cells = append(cells, columnName(x)+" "+l.cellText(x, n-1))
+	}
+	l.say("Row %d: %s.", n, strings.Join(cells, ", "))
+}
+
+func (l *LineGame) status() string {
+	g := l.game
+	b := &g.board
+	var state string
+	switch b.GetState() {
+	case model.InProgress:
+		left := b.GetSize()*b.GetSize() - b.GetBlackHolesCount() - b.GetOpenedCount()
+		state = fmt.Sprintf("In progress, %d safe cells left", left)
+	case model.Won:
+		state = "Won"
+	case model.Lost:
+		state = "Lost"
+	}
+	status := fmt.Sprintf("%s, %d seconds, %d moves, %d of %d flags", state, int(b.GetElapsed().Seconds()), b.GetMoves(),
+		b.GetFlagsCount(), b.GetBlackHolesCount())
+	if b.IsPractice() {
+		status += ", practice game"
+	}
+	if g.daily != "" {
+		status += ", " + l.dailyText()
+	}
+	return status + "."
+}
+
+func (l *LineGame) hint() {
+	x, y, ok := l.game.board.Hint()
+	if !ok {
+		l.say("No cell can be proven safe, you have to guess.")
+		return
+	}
+	l.say("%s is safe.", cellName(x, y))
+}
+
+func (l *LineGame) undo(undo bool) {
+	action := replay.Redo
+	if undo {
+		l.game.board.Undo()
+		action = replay.Undo
+	} else {
+		l.game.board.Redo()
+	}
+	if len(l.events) == 0 && undo {
+		l.say("Nothing to undo.")
+		return
+	}
+	if len(l.events) == 0 {
+		l.say("Nothing to redo.")
+		return
+	}
+	l.game.record(action, 0, 0)
+	l.sayEvents()
+}
+
+func (l *LineGame) newBoard(args []string) {
+	g := l.game
+	d := g.difficulty
+	if len(args) > 0 {
+		var err error
+		if d, err = lineDifficulty(args[0]); err != nil {
+			l.say("%v.", err)
+			return
+		}
+	}
+	var board model.Board
+	var err error
+	if d.Name == model.Daily.Name {
+		board, err = model.DailyCode(time.Now()).NewBoard()
+	} else {
+		board, err = newBoard(d)
+	}
+	if err != nil {
+		l.say("Can't make the board: %v.", err)
+		return
+	}
+	board.SetPractice(g.practice)
+	g.setBoard(board, d)
+	l.listen()
+	fmt.Fprintln(l.out, l.intro())
+	if g.daily != "" {
+		l.say("This is the %s.", l.dailyText())
+	}
+}
+
+func (l *LineGame) dailyText() string {
+	if l.game.unranked {
+		return "daily challenge retry, it doesn't count for high scores"
+	}
+	return fmt.Sprintf("daily challenge, streak %d", l.game.streak)
+}
+
+func lineDifficulty(name string) (model.Difficulty, error) {
+	if name == model.Daily.Name {
+		return model.Daily, nil
+	}
+	return model.DifficultyByName(name)
+}
+
+// cell reads the cell argument, it tells the player what's wrong if it can't.
+func (l *LineGame) cell(args []string) (x, y int, ok bool) {
+	if len(args) != 1 {
+		l.say("Which cell? I.e. c5.")
+		return 0, 0, false
+	}
+	x, y, err := parseCellName(args[0], l.game.board.GetSize())
+	if err != nil {
+		l.say("%v.", err)
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+// cellText is what the player knows about the cell.
+func (l *LineGame) cellText(x, y int) string {
+	b := &l.game.board
+	switch {
+	case b.IsOpened(x, y) && b.IsBlackHole(x, y):
+		return "a black hole"
+	case b.IsFlagged(x, y):
+		return "flagged"
+	case !b.IsOpened(x, y):
+		return "closed"
+	case b.GetNeighboursCount(x, y) == 0:
+		return "empty"
+	}
+	return strconv.Itoa(b.GetNeighboursCount(x, y))
+}
+
+func cellsCount(n int) string {
+	if n == 1 {
+		return "1 cell"
+	}
+	return fmt.Sprintf("%d cells", n)
+}
+
+// columnName names the columns as spreadsheets do: a to z, then aa, ab and so on.
+func columnName(x int) string {
+	name := ""
+	for x++; x > 0; x = (x - 1) / 26 {
+		name = string(rune('a'+(x-1)%26)) + name
+	}
+	return name
+}
+
+func cellName(x, y int) string {
+	return fmt.Sprintf("%s%d", columnName(x), y+1)
+}
+
+// parseCellName reads a cell named by cellName.
+func parseCellName(name string, size int) (x, y int, err error) {
+	i := strings.IndexFunc(name, func(r rune) bool { return r < 'a' || r > 'z' })
+	if i <= 0 {
+		return 0, 0, fmt.Errorf("%s isn't a cell, cells are a column letter and a row number, i.e. c5", name)
+	}
+	column := 0
+	for _, r := range name[:i] {
+		// a longer name is out of the board anyway, stopping keeps it from overflowing
+		if column > size {
+			break
+		}
+		column = column*26 + int(r-'a') + 1
+	}
+	row, err := strconv.Atoi(name[i:])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s isn't a cell, cells are a column letter and a row number, i.e. c5", name)
+	}
+	if column > size || row < 1 || row > size {
+		return 0, 0, fmt.Errorf("%s is out of the board, columns go from a to %s and rows from 1 to %d", name, columnName(size-1), size)
+	}
+	return column - 1, row - 1, nil
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		x    int
+		want string
+	}{
+		{x: 0, want: "a"},
+		{x: 2, want: "c"},
+		{x: 25, want: "z"},
+		{x: 26, want: "aa"},
+		{x: 27, want: "ab"},
+		{x: 51, want: "az"},
+		{x: 52, want: "ba"},
+		{x: 701, want: "zz"},
+		{x: 702, want: "aaa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := columnName(tt.x); got != tt.want {
+				t.Errorf("columnName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCellName(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		wantX   int
+		wantY   int
+		wantErr bool
+	}{
+		{name: "a1", size: 9},
+		{name: "c5", size: 9, wantX: 2, wantY: 4},
+		{name: "i9", size: 9, wantX: 8, wantY: 8},
+		{name: "z30", size: 30, wantX: 25, wantY: 29},
+		{name: "aa1", size: 30, wantX: 26},
+		{name: "ad30", size: 30, wantX: 29, wantY: 29},
+		{name: "j1", size: 9, wantErr: true},
+		{name: "a10", size: 9, wantErr: true},
+		{name: "a0", size: 9, wantErr: true},
+		{name: "ae1", size: 30, wantErr: true},
+		{name: "zzzzzzzzzzzzzzzz1", size: 30, wantErr: true},
+		{name: "5c", size: 9, wantErr: true},
+		{name: "c", size: 9, wantErr: true},
+		{name: "c-1", size: 9, wantErr: true},
+		{name: "c5x", size: 9, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, err := parseCellName(tt.name, tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCellName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if x != tt.wantX || y != tt.wantY {
+				t.Errorf("parseCellName() = %d,%d, want %d,%d", x, y, tt.wantX, tt.wantY)
+			}
+			if !tt.wantErr && cellName(x, y) != tt.name {
+				t.Errorf("cellName() = %q, want %q", cellName(x, y), tt.name)
+			}
+		})
+	}
+}
+
+func TestLineGame_handle(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands string
+		want     []string
+		notWant  []string
+	}{
+		{name: "open a number", commands: "open e1", want: []string{"Opened 1 cell; e1 is 1."}},
+		{name: "short command", commands: "o e1", want: []string{"Opened 1 cell; e1 is 1."}},
+		{name: "upper case", commands: "OPEN E1", want: []string{"Opened 1 cell; e1 is 1."}},
+		{name: "open and win", commands: "open c3", want: []string{"Opened 34 cells; c3 is empty.", "You won in", "Type new for another board."}},
+		{name: "open a black hole", commands: "open f1", want: []string{"f1 is a black hole, you lost after 1 moves."}},
+		{name: "game over", commands: "open f1\nopen a1", want: []string{"The game is over, type new for another board."}},
+		{name: "nothing changed", commands: "open e1\nopen e1", want: []string{"Nothing changed, e1 is 1."}},
+		{name: "flag", commands: "flag f1\nf f1", want: []string{"Flagged f1, 1 of 2 flags.", "Unflagged f1, 0 of 2 flags."}},
+		{name: "describe", commands: "describe a1", want: []string{"a1 is closed."}},
+		{name: "describe around", commands: "open e1\ndescribe around f1", want: []string{"Around f1: e1 1, e2 closed, f2 closed."}},
+		{name: "row", commands: "row 1", want: []string{"Row 1: a closed, b closed, c closed, d closed, e closed, f closed."}},
+		{name: "row out of the board", commands: "row 7", want: []string{"Rows go from 1 to 6."}},
+		{name: "row without a number", commands: "row", want: []string{"Which row? I.e. row 5."}},
+		{name: "cell out of the board", commands: "open g1", want: []string{"g1 is out of the board, columns go from a to f and rows from 1 to 6."}},
+		{name: "not a cell", commands: "open 5c", want: []string{"5c isn't a cell"}},
+		{name: "no cell", commands: "open", want: []string{"Which cell? I.e. c5."}},
+		{name: "status", commands: "flag a1\nstatus", want: []string{"In progress, 34 safe cells left", "1 of 2 flags."}},
+		{name: "undo", commands: "flag a1\nundo\nredo", want: []string{"Unflagged a1, 0 of 2 flags.", "Flagged a1, 1 of 2 flags."}},
+		{name: "nothing to undo", commands: "undo\nredo", want: []string{"Nothing to undo.", "Nothing to redo."}},
+		{name: "help", commands: "help", want: []string{"new [easy|medium|hard|daily]: start another board"}},
+		{name: "no code", commands: "code", want: []string{"This board has no code."}},
+		{name: "new", commands: "new easy", want: []string{"New easy board, 8 by 8 with 10 black holes. Columns a to h, rows 1 to 8."}},
+		{name: "unknown difficulty", commands: "new impossible", notWant: []string{"New "}},
+		{name: "n isn't new", commands: "open e1\nn\nstatus", want: []string{"Unknown command n", "33 safe cells left"}},
+		{name: "quit", commands: "quit\nopen e1", notWant: []string{"Opened"}},
+		{name: "blank lines", commands: "\n  \nopen e1", want: []string{"Opened 1 cell; e1 is 1."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, out := newLineGame(t, jumpLayout)
+			l.in = strings.NewReader(tt.commands + "\n")
+			if err := l.Start(); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			// the intro is about the board of the config, not the layout
+			answers := out.String()[strings.Index(out.String(), "Type help"):]
+			for _, want := range tt.want {
+				if !strings.Contains(answers, want) {
+					t.Errorf("answers = %q, want %q", answers, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(answers, notWant) {
+					t.Errorf("answers = %q, don't want %q", answers, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestLineGame_record(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands string
+		want     []replay.Action
+	}{
+		{name: "open", commands: "open e1", want: []replay.Action{replay.Open}},
+		{name: "nothing changed", commands: "open e1\nopen e1\nflag e1\nchord a1", want: []replay.Action{replay.Open}},
+		{name: "flag and undo", commands: "flag a1\nundo\nredo", want: []replay.Action{replay.Flag, replay.Undo, replay.Redo}},
+		{name: "nothing to undo", commands: "undo\nredo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newLineGame(t, jumpLayout)
+			l.game.replays = t.TempDir()
+			l.game.startRecording()
+			l.in = strings.NewReader(tt.commands + "\n")
+			if err := l.Start(); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			l.game.stopRecording()
+			if got := recorded(t, l.game.replays); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recorded %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newLineGame returns a line game playing the layout and what it writes.
+func newLineGame(t *testing.T, layout string) (*LineGame, *strings.Builder) {
+	t.Helper()
+	out := &strings.Builder{}
+	l, err := NewLineGame(Config{Difficulty: model.Easy}, nil, out)
+	if err != nil {
+		t.Fatalf("NewLineGame() error = %v", err)
+	}
+	board, err := model.ParseLayout(layout)
+	if err != nil {
+		t.Fatalf("ParseLayout() error = %v", err)
+	}
+	l.game.setBoard(board, model.Difficulty{Name: "test", Size: board.GetSize(), BlackHolesCount: board.GetBlackHolesCount()})
+	l.listen()
+	return l, out
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+type menuEntry struct {
+	title string
+	// detail is shown after the title if set, i.e. the current setting
+	detail func(g *Game) string
+	action func(g *Game)
+}
+
+var menu = []menuEntry{
+	{title: "Back to the game", action: func(g *Game) { g.view = boardView }},
+	{title: "New game", action: (*Game).restart},
+	{title: "Save game", action: func(g *Game) { g.openSlots(saveSlots) }},
+	{title: "Load game", action: func(g *Game) { g.openSlots(loadSlots) }},
+	{title: "High scores", action: func(g *Game) { g.view = scoresView }},
+	{title: "Statistics", action: func(g *Game) { g.view = statsView }},
+	{title: "Theme", detail: func(g *Game) string { return g.theme.Name }, action: (*Game).nextTheme},
+	{title: "Quit", action: (*Game).quit},
+}
+
+// handleMenu returns true if the event was consumed by the menu.
+func (g *Game) handleMenu(event *tcell.EventKey) bool {
+	a, _ := g.keys.action(event)
+	switch {
+	case event.Key() == tcell.KeyUp || a == MoveUp:
+		g.menuItem = (g.menuItem + len(menu) - 1) % len(menu)
+	case event.Key() == tcell.KeyDown || a == MoveDown:
+		g.menuItem = (g.menuItem + 1) % len(menu)
+	case event.Key() == tcell.KeyEnter:
+		menu[g.menuItem].action(g)
+	default:
+		return false
+	}
+	return true
+}
+
+func (g *Game) printMenu(s tcell.Style) {
+	for i, entry := range menu {
+		style := s
+		if i == g.menuItem {
+			style = s.Reverse(true)
+		}
+		title := entry.title
+		if entry.detail != nil {
+			title += ": " + entry.detail(g)
+		}
+		for j, r := range []rune(title) {
+			g.screen.SetContent(j+BannerPadding, g.location.y+i*2, r, nil, style)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/race"
+	"strings"
+	"time"
+)
+
+// followRace applies the messages of the host until the connection is closed.
+func (g *Game) followRace() {
+	for m := range g.race.Messages() {
+		g.mu.Lock()
+		g.onRaceMessage(m)
+		g.mu.Unlock()
+		g.requestRedraw()
+	}
+	g.mu.Lock()
+	g.racing, g.countdown = false, 0
+	if !g.done {
+		g.raceError = "Lost the connection to the host"
+	}
+	g.mu.Unlock()
+	g.requestRedraw()
+}
+
+func (g *Game) onRaceMessage(m race.Message) {
+	switch m.Type {
+	case race.LobbyMessage:
+		g.lobby = m.Players
+	case race.CountdownMessage:
+		g.countdown, g.ranking = m.Seconds, nil
+		g.view = raceView
+	case race.StartMessage:
+		c, err := model.ParseCode(m.Code)
+		if err == nil {
+			var board model.Board
+			if board, err = c.NewBoard(); err == nil {
+				g.countdown, g.ranking, g.raceError = 0, nil, ""
+				g.racing = true
+				g.setBoard(board, model.CustomDifficulty(c.Size, c.BlackHolesCount))
+				g.sendProgress()
+				return
+			}
+		}
+		g.raceError = fmt.Sprintf("Can't play the board of the host: %v", err)
+	case race.ProgressMessage:
+		g.racers = m.Players
+	case race.RankingMessage:
+		g.racers, g.ranking = nil, m.Players
+		g.racing = false
+	}
+}
+
+// sendProgress tells the host how far the player got, the race goes on for the others if that fails.
+func (g *Game) sendProgress() {
+	if g.race == nil || !g.racing {
+		return
+	}
+	if err := g.race.Progress(g.board.GetOpenedCount(), g.board.GetState()); err != nil {
+		g.raceError = fmt.Sprintf("Can't reach the host: %v", err)
+	}
+}
+
+// handleRace returns true if the event was consumed by the race, it keeps the players on the board of the race.
+func (g *Game) handleRace(event *tcell.EventKey, a Action) bool {
+	if g.race == nil {
+		return false
+	}
+	if g.view == raceView {
+		if event.Key() == tcell.KeyEnter || a == Restart {
+			g.startRace()
+		}
+		return a != NextTheme
+	}
+	switch {
+	case a == Menu || a == Pause || a == Undo || a == Redo || a == Hint:
+		return true
+	case g.view == boardView && !g.racing && (a == Restart || event.Key() == tcell.KeyEnter):
+		g.startRace()
+		return true
+	}
+	return a == Restart
+}
+
+func (g *Game) startRace() {
+	if g.startRaceFunc == nil || g.racing || g.countdown > 0 {
+		return
+	}
+	if err := g.startRaceFunc(); err != nil {
+		g.raceError = err.Error()
+		return
+	}
+	g.raceError = ""
+}
+
+// raceHelp is the banner of the race: the lobby or the standings.
+func (g *Game) raceHelp() string {
+	if g.raceError != "" && g.view == boardView {
+		return g.raceError
+	}
+	if g.view == raceView || g.racers == nil {
+		return fmt.Sprintf("%d in the lobby, %s", len(g.lobby), g.startHelp())
+	}
+	var standings []string
+	for _, p := range g.racers {
+		if p.Name == g.race.Name {
+			continue
+		}
+		switch p.State {
+		case race.Won:
+			standings = append(standings, fmt.Sprintf("%s %s", p.Name, g.glyphs.star))
+		case race.Lost:
+			standings = append(standings, fmt.Sprintf("%s %d%%%c", p.Name, p.Percent, g.glyphs.blackHole))
+		case race.Left:
+			standings = append(standings, p.Name+" left")
+		default:
+			standings = append(standings, fmt.Sprintf("%s %d%%", p.Name, p.Percent))
+		}
+	}
+	info := strings.Join(standings, "  ")
+	if width := BannerWidth - BannerPadding - 1; len([]rune(info)) > width {
+		info = string([]rune(info)[:width-3]) + "..."
+	}
+	return info
+}
+
+func (g *Game) startHelp() string {
+	if g.startRaceFunc == nil {
+		return "waiting for the host to start, " + g.keys.help("quit:quit")
+	}
+	return "enter: start the race, " + g.keys.help("quit:quit")
+}
+
+// printLobby shows the players waiting for the race and the countdown.
+func (g *Game) printLobby(s tcell.Style) {
+	switch {
+	case g.raceError != "":
+		g.printMessage(g.theme.Lost, g.raceError)
+	case g.countdown > 0:
+		g.printMessage(s.Bold(true), fmt.Sprintf("The race starts in %d...", g.countdown))
+	default:
+		g.printMessage(s, "Waiting for the race to start")
+	}
+	lines := []string{"Players:"}
+	for _, p := range g.lobby {
+		name := p.Name
+		if name == g.race.Name {
+			name += " (you)"
+		}
+		lines = append(lines, "  "+name)
+	}
+	g.printLines(s, BannerHeight+1, lines)
+}
+
+// raceLines are shown under the result of the race board.
+func (g *Game) raceLines() []string {
+	if g.raceError != "" {
+		return []string{g.raceError}
+	}
+	if g.ranking == nil {
+		if g.racing && g.board.GetState() != model.InProgress {
+			return []string{"Waiting for the others to finish the race"}
+		}
+		return nil
+	}
+	lines := []string{"Ranking:"}
+	for _, p := range g.ranking {
+		line := fmt.Sprintf("%d. %-*s  %3d%%  %s", p.Rank, race.MaxNameLength, p.Name, p.Percent, p.State)
+		if p.State == race.Won || p.State == race.Lost {
+			line += fmt.Sprintf(" in %.2fs", (time.Duration(p.TimeMs) * time.Millisecond).Seconds())
+		}
+		lines = append(lines, line)
+	}
+	return append(lines, g.startHelp())
+}
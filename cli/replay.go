@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"sync"
+	"time"
+)
+
+const replayTick = 20 * time.Millisecond
+
+var replaySpeeds = []float64{0.25, 0.5, 1, 2, 4, 8, 16}
+
+// Replay plays a recorded game back with the same renderer as the game.
+type Replay struct {
+	mu   sync.Mutex
+	game *Game
+	log  replay.Log
+	// applied is the number of events already played
+	applied int
+	// clock is the playback position in the log time
+	clock  time.Duration
+	paused bool
+	speed  int
+}
+
+// NewReplay plays the log with the themes, the theme, the colour mode and the glyph set of the config.
+func NewReplay(log replay.Log, cfg Config) (*Replay, error) {
+	s, err := tcell.NewScreen()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Init(); err != nil {
+		return nil, err
+	}
+	r, err := newReplay(log, cfg, s)
+	if err != nil {
+		s.Fini()
+		return nil, err
+	}
+	return r, nil
+}
+
+func newReplay(log replay.Log, cfg Config, s tcell.Screen) (*Replay, error) {
+	display := Config{Difficulty: log.Header.Difficulty, Player: log.Header.Player, Themes: cfg.Themes, Theme: cfg.Theme,
+		Colors: cfg.Colors, Glyphs: cfg.Glyphs, Term: cfg.Term}
+	game, err := newGame(display, log.BoardAt(0), s)
+	if err != nil {
+		return nil, err
+	}
+	return &Replay{game: game, log: log, speed: 2}, nil
+}
+
+func (r *Replay) Start() {
+	r.game.screen.SetStyle(r.game.theme.Base)
+
+	go r.play(r.game.theme.Base)
+
+	for {
+		switch event := r.game.screen.PollEvent().(type) {
+		case *tcell.EventResize:
+			r.game.screen.Sync()
+		case *tcell.EventKey:
+			if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyCtrlC {
+				r.game.screen.Fini()
+				return
+			}
+			r.mu.Lock()
+			r.handleEventKey(event)
+			r.mu.Unlock()
+		}
+	}
+}
+
+func (r *Replay) handleEventKey(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyRight:
+		r.paused = true
+		if r.applied < len(r.log.Events) {
+			r.seek(r.applied + 1)
+		}
+	case tcell.KeyLeft:
+		r.paused = true
+		if r.applied > 0 {
+			r.seek(r.applied - 1)
+		}
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case ' ':
+			r.paused = !r.paused
+			if !r.paused && r.applied == len(r.log.Events) {
+				r.seek(0)
+			}
+		case '+', '=':
+			if r.speed < len(replaySpeeds)-1 {
+				r.speed++
+			}
+		case '-':
+			if r.speed > 0 {
+				r.speed--
+			}
+		}
+	}
+}
+
+// seek shows the board after the first n events.
+func (r *Replay) seek(n int) {
+	r.game.replaceBoard(r.log.BoardAt(n))
+	r.applied = n
+	r.clock = 0
+	r.game.cursor = r.game.location
+	if n > 0 {
+		last := r.log.Events[n-1]
+		r.clock = last.At
+		r.moveCursor(last)
+	}
+}
+
+func (r *Replay) moveCursor(e replay.Event) {
+	r.game.cursor = point{x: r.game.location.x + e.X*XAxisStep, y: r.game.location.y + e.Y*YAxisStep}
+}
+
+func (r *Replay) play(s tcell.Style) {
+	for {
+		r.mu.Lock()
+		if !r.paused {
+			r.clock += time.Duration(float64(replayTick) * replaySpeeds[r.speed])
+			for r.applied < len(r.log.Events) && r.log.Events[r.applied].At <= r.clock {
+				e := r.log.Events[r.applied]
+				e.Apply(&r.game.board)
+				r.moveCursor(e)
+				r.applied++
+			}
+			if r.applied == len(r.log.Events) {
+				r.paused = true
+			}
+		}
+		r.print(s)
+		r.mu.Unlock()
+
+		time.Sleep(replayTick)
+	}
+}
+
+func (r *Replay) print(s tcell.Style) {
+	g := r.game
+	g.screen.Clear()
+	g.printBanner(g.theme.Banner, "space: pause, arrows: step, +/-: speed, esc: quit")
+	g.printBoard(s)
+	g.printCursor(g.theme.Cursor)
+
+	status := fmt.Sprintf("Replay of %s: %d/%d  %.1fs  x%g", g.player, r.applied, len(r.log.Events), r.clock.Seconds(), replaySpeeds[r.speed])
+	if r.paused {
+		status += "  paused"
+	}
+	if g.board.GetState() == model.InProgress {
+		g.printMessage(s, fmt.Sprintf("%-*s", BannerWidth-BannerPadding-1, status))
+	} else {
+		g.printLines(s, g.location.y+g.board.GetSize()*YAxisStep+5, []string{status})
+	}
+	g.screen.Show()
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"testing"
+)
+
+func TestNewReplay(t *testing.T) {
+	board, err := model.ParseLayout(jumpLayout)
+	if err != nil {
+		t.Fatalf("ParseLayout() error = %v", err)
+	}
+	log := replay.Log{Header: replay.Header{Difficulty: model.CustomDifficulty(6, 2), Player: "ann", Board: board}}
+	s := tcell.NewSimulationScreen("UTF-8")
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	defer s.Fini()
+	theme := BuiltinThemes()[1]
+
+	r, err := newReplay(log, Config{Theme: theme.Name, Colors: Monochrome, Glyphs: ASCIIGlyphs}, s)
+	if err != nil {
+		t.Fatalf("newReplay() error = %v", err)
+	}
+	if r.game.theme.Name != theme.Name || r.game.colors != Monochrome || r.game.glyphs.flag != asciiGlyphs.flag {
+		t.Errorf("replay theme %s, colours %s, flag %q, want %s, %s, %q", r.game.theme.Name, r.game.colors, r.game.glyphs.flag,
+			theme.Name, Monochrome, asciiGlyphs.flag)
+	}
+	if _, err := newReplay(log, Config{Theme: "nope"}, s); err == nil {
+		t.Errorf("newReplay() accepted an unknown theme")
+	}
+}
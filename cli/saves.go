@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/save"
+	"time"
+)
+
+type slotsMode int
+
+const (
+	saveSlots slotsMode = iota
+	loadSlots
+)
+
+func (g *Game) savedGame() save.Game {
+	return save.Game{
+		Difficulty: g.difficulty,
+		Player:     g.player,
+		SavedAt:    time.Now(),
+		Board:      g.board,
+	}
+}
+
+func (g *Game) load(saved save.Game) {
+	g.setBoard(saved.Board, saved.Difficulty)
+}
+
+func (g *Game) openSlots(mode slotsMode) {
+	g.slotsMode = mode
+	g.slotsInfo = ""
+	g.view = slotsView
+}
+
+// handleSlots returns true if the event was consumed by the slots view.
+func (g *Game) handleSlots(event *tcell.EventKey) bool {
+	if event.Key() != tcell.KeyRune || event.Rune() < '1' || event.Rune() > rune('0'+save.Slots) {
+		return false
+	}
+	if g.saves == nil {
+		g.slotsInfo = "Saving is disabled"
+		return true
+	}
+	slot := save.SlotName(int(event.Rune() - '0'))
+	switch g.slotsMode {
+	case saveSlots:
+		if err := g.saves.Save(slot, g.savedGame()); err != nil {
+			g.slotsInfo = fmt.Sprintf("Can't save the game: %v", err)
+			return true
+		}
+		g.view = boardView
+	case loadSlots:
+		saved, err := g.saves.Load(slot)
+		if err != nil {
+			g.slotsInfo = fmt.Sprintf("Can't load the game: %v", err)
+			return true
+		}
+		// the slot could be loaded again after every loss
+		saved.Board.SetReloaded()
+		g.load(saved)
+	}
+	return true
+}
+
+// handleResume resumes the autosaved game with the open key or forfeits it with the restart key.
+func (g *Game) handleResume(event *tcell.EventKey) {
+	a, _ := g.keys.action(event)
+	switch a {
+	case Open:
+		// read it again so the time spent on this prompt isn't counted
+		saved, err := g.saves.Load(save.Autosave)
+		if err != nil {
+			g.view = boardView
+			g.notice = fmt.Sprintf("Can't resume the last game: %v", err)
+			break
+		}
+		g.load(saved)
+	case Restart:
+		g.forfeitSaved(*g.autosaved)
+		g.view = boardView
+	default:
+		return
+	}
+	g.saves.Delete(save.Autosave)
+	g.autosaved = nil
+}
+
+func (g *Game) printSlots(s tcell.Style) {
+	title := "Save the game to a slot"
+	if g.slotsMode == loadSlots {
+		title = "Load a game from a slot"
+	}
+	lines := []string{title, ""}
+	for i := 1; i <= save.Slots; i++ {
+		line := fmt.Sprintf("%d  empty", i)
+		if g.saves != nil {
+			if saved, err := g.saves.Load(save.SlotName(i)); err == nil {
+				line = fmt.Sprintf("%d  %s", i, describeSave(saved))
+			}
+		}
+		lines = append(lines, line)
+	}
+	if g.slotsInfo != "" {
+		lines = append(lines, "", g.slotsInfo)
+	}
+	g.printLines(s, g.location.y, lines)
+}
+
+func (g *Game) printResume(s tcell.Style) {
+	g.printLines(s, g.location.y, []string{"You have an unfinished game:", "", describeSave(*g.autosaved)})
+}
+
+func describeSave(saved save.Game) string {
+	state := "in progress"
+	switch saved.Board.GetState() {
+	case model.Won:
+		state = "won"
+	case model.Lost:
+		state = "lost"
+	}
+	return fmt.Sprintf("%s, %s, %d moves, %ds, saved %s", saved.Difficulty.Key(), state,
+		saved.Board.GetMoves(), int(saved.Board.GetElapsed().Seconds()), saved.SavedAt.Format("2006-01-02 15:04"))
+}
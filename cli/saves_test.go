@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/save"
+	"testing"
+)
+
+func newSavesGame(t *testing.T) (*Game, save.Store) {
+	t.Helper()
+	saves := save.NewStore(t.TempDir())
+	board, err := newBoard(model.Easy)
+	if err != nil {
+		t.Fatalf("newBoard() error = %v", err)
+	}
+	g, err := newGame(Config{Difficulty: model.Easy, Saves: &saves}, board, nil)
+	if err != nil {
+		t.Fatalf("newGame() error = %v", err)
+	}
+	return g, saves
+}
+
+func runeKey(r rune) *tcell.EventKey {
+	return tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+}
+
+func TestGame_handleResume(t *testing.T) {
+	tests := []struct {
+		name       string
+		autosave   bool
+		wantNotice bool
+	}{
+		{name: "resumed", autosave: true},
+		{name: "autosave gone", wantNotice: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, saves := newSavesGame(t)
+			saved := g.savedGame()
+			if tt.autosave {
+				if err := saves.Save(save.Autosave, saved); err != nil {
+					t.Fatalf("Save() error = %v", err)
+				}
+			}
+			g.autosaved, g.view = &saved, resumeView
+			g.handleResume(runeKey(' '))
+			if g.view != boardView || g.autosaved != nil || (g.notice != "") != tt.wantNotice {
+				t.Errorf("view = %v, autosaved = %v, notice = %q, want the board and notice %v", g.view, g.autosaved, g.notice, tt.wantNotice)
+			}
+			if g.board.IsPractice() {
+				t.Errorf("resumed game is a practice game")
+			}
+		})
+	}
+}
+
+func TestGame_handleResumeKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         rune
+		wantResumed bool
+		wantNew     bool
+	}{
+		{name: "open resumes", key: ' ', wantResumed: true},
+		{name: "restart of the preset starts a new game", key: 'N', wantNew: true},
+		{name: "other keys wait", key: 'n'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, saves := newSavesGame(t)
+			keys, err := NewBindings("vim", nil)
+			if err != nil {
+				t.Fatalf("NewBindings() error = %v", err)
+			}
+			g.keys = keys
+			saved := g.savedGame()
+			saved.Board.ToggleFlag(0, 0)
+			if err := saves.Save(save.Autosave, saved); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			g.autosaved, g.view = &saved, resumeView
+			g.handleResume(runeKey(tt.key))
+			if left := g.view == boardView && g.autosaved == nil; left != (tt.wantResumed || tt.wantNew) {
+				t.Errorf("view = %v, autosaved = %v, want the prompt left %v", g.view, g.autosaved, tt.wantResumed || tt.wantNew)
+			}
+			if resumed := g.board.GetFlagsCount() == 1; resumed != tt.wantResumed {
+				t.Errorf("flags = %d, want the saved game resumed %v", g.board.GetFlagsCount(), tt.wantResumed)
+			}
+		})
+	}
+}
+
+func TestGame_loadSlot(t *testing.T) {
+	g, saves := newSavesGame(t)
+	if err := saves.Save(save.SlotName(1), g.savedGame()); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	g.openSlots(loadSlots)
+	g.handleSlots(runeKey('1'))
+	if g.view != boardView || !g.board.IsPractice() {
+		t.Errorf("view = %v, IsPractice() = %v, want a practice game on the board", g.view, g.board.IsPractice())
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/score"
+	"time"
+)
+
+// finish records the game result once the game is over.
+func (g *Game) finish() {
+	if g.scores == nil || g.board.IsPractice() {
+		return
+	}
+	if g.daily != "" {
+		g.recordDaily(g.outcome())
+		return
+	}
+	res, err := g.scores.RecordGame(g.difficulty.Key(), g.outcome())
+	g.result, g.scoreError = &res, err
+}
+
+func (g *Game) outcome() score.Outcome {
+	lostX, lostY, _ := g.board.GetLostAt()
+	return score.Outcome{
+		Entry: score.Entry{
+			Player:  g.player,
+			Time:    g.board.GetElapsed(),
+			Moves:   g.board.GetMoves(),
+			ThreeBV: g.metrics.ThreeBV,
+			Seed:    g.board.GetSeed(),
+			Date:    time.Now(),
+		},
+		Won:         g.board.GetState() == model.Won,
+		BoardSize:   g.board.GetSize(),
+		CellsOpened: g.board.GetOpenedCount(),
+		LostX:       lostX,
+		LostY:       lostY,
+	}
+}
+
+// scoresKey is the high score table of the board, every daily challenge has its own.
+func (g *Game) scoresKey() string {
+	if g.daily != "" {
+		return score.DailyKey(g.daily)
+	}
+	return g.difficulty.Key()
+}
+
+func (g *Game) printScores(s tcell.Style) {
+	lines := []string{fmt.Sprintf("High scores: %s", g.scoresKey()), ""}
+	if g.scores == nil {
+		g.printLines(s, g.location.y, append(lines, "High scores are disabled"))
+		return
+	}
+	entries, err := g.scores.Top(g.scoresKey())
+	if err != nil {
+		g.printLines(s, g.location.y, append(lines, fmt.Sprintf("Can't read high scores: %v", err)))
+		return
+	}
+	if len(entries) == 0 {
+		lines = append(lines, "No games won yet")
+	} else {
+		lines = append(lines, fmt.Sprintf("%-3s %-12s %9s %6s %5s  %s", "#", "Player", "Time", "Moves", "3BV", "Date"))
+	}
+	for i, e := range entries {
+		lines = append(lines, formatEntry(fmt.Sprintf("%d", i+1), e))
+	}
+	if best, ok, err := g.scores.PersonalBest(g.scoresKey(), g.player); err == nil && ok {
+		lines = append(lines, "", formatEntry("you", best))
+	}
+	g.printLines(s, g.location.y, lines)
+}
+
+func formatEntry(rank string, e score.Entry) string {
+	player := []rune(e.Player)
+	if len(player) > 12 {
+		player = player[:12]
+	}
+	return fmt.Sprintf("%-3s %-12s %8.2fs %6d %5d  %s", rank, string(player), e.Time.Seconds(), e.Moves, e.ThreeBV, e.Date.Format("2006-01-02"))
+}
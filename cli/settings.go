@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SettingsFileName is the display settings file in the game config directory.
+const SettingsFileName = "settings.json"
+
+// Settings are the defaults of the display flags.
+type Settings struct {
+	Colors string `json:"colors"`
+	Glyphs string `json:"glyphs"`
+}
+
+// LoadSettings reads the settings file, a missing file gives empty settings.
+func LoadSettings(path string) (Settings, error) {
+	var s Settings
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("can't read settings from %s: %w", path, err)
+	}
+	return s, nil
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"sort"
+	"strings"
+	"time"
+)
+
+const histogramBuckets = 8
+const histogramWidth = 30
+
+// heatmapShades go from no losses to the most frequent loss position.
+var heatmapShades = []rune(" .:-=+*#%@")
+
+func (g *Game) printStatistics(s tcell.Style) {
+	if g.scores == nil {
+		g.printLines(s, g.location.y, []string{"Statistics are disabled"})
+		return
+	}
+	all, err := g.scores.Stats()
+	if err != nil {
+		g.printLines(s, g.location.y, []string{fmt.Sprintf("Can't read statistics: %v", err)})
+		return
+	}
+
+	lines := []string{fmt.Sprintf("%-22s %6s %5s %5s %6s %7s", "Difficulty", "Played", "Won", "Lost", "Win %", "Streak")}
+	keys := make([]string, 0, len(all))
+	for key := range all {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		st := all[key]
+		lines = append(lines, fmt.Sprintf("%-22s %6d %5d %5d %5.1f%% %3d/%-3d",
+			key, st.Played, st.Won, st.Lost, st.WinRate()*100, st.CurrentStreak, st.BestStreak))
+	}
+
+	current := all[g.difficulty.Key()]
+	lines = append(lines, "",
+		fmt.Sprintf("%s: average time %.2fs, %d cells opened", g.difficulty.Key(), current.AverageTime().Seconds(), current.CellsOpened),
+		"", "Completion times:")
+	lines = append(lines, histogram(current.WonTimes, histogramBuckets, histogramWidth)...)
+	g.printLines(s, g.location.y, lines)
+
+	if len(current.LossHeatmap) > 0 {
+		top := g.location.y + len(lines) + 1
+		g.printLines(s, top, append([]string{"Black holes hit:"}, heatmap(current.LossHeatmap)...))
+	}
+}
+
+// histogram renders the times as horizontal bars, one bar per equal width time range.
+func histogram(times []time.Duration, buckets, width int) []string {
+	if len(times) == 0 {
+		return []string{"no games won yet"}
+	}
+	fastest, slowest := times[0], times[0]
+	for _, t := range times {
+		if t < fastest {
+			fastest = t
+		}
+		if t > slowest {
+			slowest = t
+		}
+	}
+	step := (slowest - fastest) / time.Duration(buckets)
+	if step == 0 {
+		step = time.Second
+		buckets = 1
+	}
+
+	counts := make([]int, buckets)
+	for _, t := range times {
+		i := int((t - fastest) / step)
+		if i >= buckets {
+			i = buckets - 1
+		}
+		counts[i]++
+	}
+	highest := 0
+	for _, c := range counts {
+		if c > highest {
+			highest = c
+		}
+	}
+
+	lines := make([]string, 0, buckets)
+	for i, c := range counts {
+		from := fastest + time.Duration(i)*step
+		bar := strings.Repeat("#", (c*width+highest-1)/highest)
+		lines = append(lines, fmt.Sprintf("%7.1fs |%-*s %d", from.Seconds(), width, bar, c))
+	}
+	return lines
+}
+
+// heatmap renders the loss counts as shaded characters, one per cell.
+func heatmap(counts [][]int) []string {
+	highest := 0
+	for _, row := range counts {
+		for _, c := range row {
+			if c > highest {
+				highest = c
+			}
+		}
+	}
+	lines := make([]string, 0, len(counts))
+	for _, row := range counts {
+		var b strings.Builder
+		for _, c := range row {
+			shade := 0
+			if c > 0 {
+				shade = 1 + (c-1)*(len(heatmapShades)-2)/maxInt(highest-1, 1)
+			}
+			b.WriteRune(heatmapShades[shade])
+		}
+		lines = append(lines, "|"+b.String()+"|")
+	}
+	return lines
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"os"
+	"strings"
+)
+
+// ThemesFileName is the user themes file in the game config directory.
+const ThemesFileName = "themes.json"
+
+const DefaultTheme = "classic"
+
+// ThemeColors are the colours of a theme as tcell colour names or #rrggbb, empty ones take the text colour.
+type ThemeColors struct {
+	Name       string `json:"name"`
+	Background string `json:"background"`
+	Text       string `json:"text"`
+	Closed     string `json:"closed"`
+	Flag       string `json:"flag"`
+	BlackHole  string `json:"black_hole"`
+	// Cursor is the background of the cell under the cursor.
+	Cursor string `json:"cursor"`
+	Banner string `json:"banner"`
+	Won    string `json:"won"`
+	Lost   string `json:"lost"`
+	// Numbers are the colours of 1 to 8.
+	Numbers []string `json:"numbers"`
+}
+
+// Theme holds the styles everything on the screen is drawn with.
+type Theme struct {
+	Name      string
+	Base      tcell.Style
+	Closed    tcell.Style
+	Flag      tcell.Style
+	BlackHole tcell.Style
+	Cursor    tcell.Style
+	Banner    tcell.Style
+	Won       tcell.Style
+	Lost      tcell.Style
+	Numbers   [8]tcell.Style
+}
+
+var builtinThemes = []ThemeColors{
+	{
+		Name: "classic", Background: "whitesmoke", Text: "black", Closed: "dimgray", Flag: "red", BlackHole: "black",
+		Cursor: "lightskyblue", Banner: "black", Won: "darkgreen", Lost: "red",
+		Numbers: []string{"blue", "green", "red", "navy", "maroon", "teal", "black", "gray"},
+	},
+	{
+		Name: "dark", Background: "#1c1c1c", Text: "#d0d0d0", Closed: "#808080", Flag: "#ff5f5f", BlackHole: "#ffffff",
+		Cursor: "#5f87af", Banner: "#d0d0d0", Won: "#87d75f", Lost: "#ff5f5f",
+		Numbers: []string{"#5fafff", "#87d75f", "#ff5f5f", "#af87ff", "#ffaf5f", "#5fd7d7", "#ffffff", "#a8a8a8"},
+	},
+	{
+		Name: "space", Background: "#0b0d2b", Text: "#c8c8ff", Closed: "#6c6ca8", Flag: "gold", BlackHole: "#ff00ff",
+		Cursor: "#3a3a8c", Banner: "#87afff", Won: "#87ffaf", Lost: "#ff5fd7",
+		Numbers: []string{"#87d7ff", "#afff87", "#ff8787", "#d7afff", "#ffd787", "#87ffff", "#ffffff", "#b2b2b2"},
+	},
+	{
+		Name: "high-contrast", Background: "black", Text: "white", Closed: "white", Flag: "yellow", BlackHole: "fuchsia",
+		Cursor: "yellow", Banner: "white", Won: "lime", Lost: "red",
+		Numbers: []string{"aqua", "lime", "red", "fuchsia", "yellow", "white", "aqua", "lime"},
+	},
+}
+
+// NewTheme checks the colours and turns them into the styles.
+func NewTheme(c ThemeColors) (Theme, error) {
+	var err error
+	color := func(name string, fallback tcell.Color) tcell.Color {
+		if name == "" || err != nil {
+			return fallback
+		}
+		res := tcell.GetColor(strings.ToLower(name))
+		if res == tcell.ColorDefault && !strings.EqualFold(name, "default") {
+			err = fmt.Errorf("unknown colour %q in theme %s", name, c.Name)
+		}
+		return res
+	}
+	if c.Name == "" {
+		return Theme{}, fmt.Errorf("theme without a name")
+	}
+	if len(c.Numbers) > 8 {
+		return Theme{}, fmt.Errorf("theme %s has %d number colours, want at most 8", c.Name, len(c.Numbers))
+	}
+	background := color(c.Background, tcell.ColorDefault)
+	text := color(c.Text, tcell.ColorDefault)
+	base := tcell.StyleDefault.Background(background).Foreground(text)
+	t := Theme{
+		Name:      c.Name,
+		Base:      base,
+		Closed:    base.Foreground(color(c.Closed, text)),
+		Flag:      base.Foreground(color(c.Flag, text)).Bold(true),
+		BlackHole: base.Foreground(color(c.BlackHole, text)).Bold(true),
+		Cursor:    base.Background(color(c.Cursor, text)).Bold(true),
+		Banner:    base.Foreground(color(c.Banner, text)),
+		Won:       base.Foreground(color(c.Won, text)),
+		Lost:      base.Foreground(color(c.Lost, text)),
+	}
+	for i := range t.Numbers {
+		name := ""
+		if i < len(c.Numbers) {
+			name = c.Numbers[i]
+		}
+		t.Numbers[i] = base.Foreground(color(name, text))
+	}
+	if err != nil {
+		return Theme{}, err
+	}
+	return t, nil
+}
+
+// BuiltinThemes returns the themes shipped with the game, classic first.
+func BuiltinThemes() []Theme {
+	themes := make([]Theme, 0, len(builtinThemes))
+	for _, c := range builtinThemes {
+		t, err := NewTheme(c)
+		if err != nil {
+			panic(err)
+		}
+		themes = append(themes, t)
+	}
+	return themes
+}
+
+// LoadThemes returns the built-in themes followed by the ones of the file, a missing file gives only the built-in ones.
+// A user theme with the name of a built-in one replaces it.
+func LoadThemes(path string) ([]Theme, error) {
+	themes := BuiltinThemes()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return themes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var colors []ThemeColors
+	if err := json.Unmarshal(data, &colors); err != nil {
+		return nil, fmt.Errorf("can't read themes from %s: %w", path, err)
+	}
+	for _, c := range colors {
+		t, err := NewTheme(c)
+		if err != nil {
+			return nil, err
+		}
+		if i := findTheme(themes, t.Name); i >= 0 {
+			themes[i] = t
+		} else {
+			themes = append(themes, t)
+		}
+	}
+	return themes, nil
+}
+
+// findTheme returns the index of the named theme, -1 if there is none.
+func findTheme(themes []Theme, name string) int {
+	for i, t := range themes {
+		if t.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// ThemeNames lists the names of the themes for the flags help and the errors.
+func ThemeNames(themes []Theme) string {
+	names := make([]string, 0, len(themes))
+	for _, t := range themes {
+		names = append(names, t.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// nextTheme switches to the theme after the current one.
+func (g *Game) nextTheme() {
+	i := findTheme(g.themes, g.theme.Name)
+	g.theme = g.themes[(i+1)%len(g.themes)].withColors(g.colors)
+}
+
+// cellStyle returns the style of the cell as the player sees it.
+func (g *Game) cellStyle(x, y int) tcell.Style {
+	switch {
+	case g.board.IsOpened(x, y) && g.board.IsBlackHole(x, y):
+		return g.theme.BlackHole
+	case g.board.IsFlagged(x, y):
+		return g.theme.Flag
+	case !g.board.IsOpened(x, y):
+		return g.theme.Closed
+	case g.board.GetNeighboursCount(x, y) > 0:
+		return g.theme.Numbers[g.board.GetNeighboursCount(x, y)-1]
+	}
+	return g.theme.Base
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func foreground(s tcell.Style) tcell.Color {
+	fg, _, _ := s.Decompose()
+	return fg
+}
+
+func TestNewTheme(t *testing.T) {
+	tests := []struct {
+		name        string
+		colors      ThemeColors
+		wantFlag    tcell.Color
+		wantNumbers [8]tcell.Color
+		wantErr     bool
+	}{
+		{
+			name:        "names and hex",
+			colors:      ThemeColors{Name: "t", Text: "Black", Flag: "#ff0000", Numbers: []string{"blue", "#00ff00"}},
+			wantFlag:    tcell.NewHexColor(0xff0000),
+			wantNumbers: [8]tcell.Color{tcell.ColorBlue, tcell.NewHexColor(0x00ff00), tcell.ColorBlack, tcell.ColorBlack, tcell.ColorBlack, tcell.ColorBlack, tcell.ColorBlack, tcell.ColorBlack},
+		},
+		{
+			name:     "empty colours take the text colour",
+			colors:   ThemeColors{Name: "t", Text: "white"},
+			wantFlag: tcell.ColorWhite,
+			wantNumbers: [8]tcell.Color{tcell.ColorWhite, tcell.ColorWhite, tcell.ColorWhite, tcell.ColorWhite, tcell.ColorWhite, tcell.ColorWhite,
+				tcell.ColorWhite, tcell.ColorWhite},
+		},
+		{name: "default colour", colors: ThemeColors{Name: "t", Flag: "default"}, wantFlag: tcell.ColorDefault},
+		{name: "unknown colour", colors: ThemeColors{Name: "t", Flag: "blurple"}, wantErr: true},
+		{name: "unknown number colour", colors: ThemeColors{Name: "t", Numbers: []string{"blue", "blurple"}}, wantErr: true},
+		{name: "no name", colors: ThemeColors{Text: "white"}, wantErr: true},
+		{name: "too many numbers", colors: ThemeColors{Name: "t", Numbers: make([]string, 9)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTheme(tt.colors)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTheme() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if foreground(got.Flag) != tt.wantFlag {
+				t.Errorf("Flag = %v, want %v", foreground(got.Flag), tt.wantFlag)
+			}
+			for i, s := range got.Numbers {
+				if foreground(s) != tt.wantNumbers[i] {
+					t.Errorf("Numbers[%d] = %v, want %v", i, foreground(s), tt.wantNumbers[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadThemes(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		wantNames  string
+		wantBanner tcell.Color
+		wantErr    bool
+	}{
+		{name: "no file", wantNames: "classic, dark, space, high-contrast", wantBanner: tcell.ColorBlack},
+		{
+			name: "new theme", file: `[{"name": "paper", "text": "black"}]`,
+			wantNames: "classic, dark, space, high-contrast, paper", wantBanner: tcell.ColorBlack,
+		},
+		{
+			name: "replaced theme", file: `[{"name": "classic", "text": "navy"}]`,
+			wantNames: "classic, dark, space, high-contrast", wantBanner: tcell.ColorNavy,
+		},
+		{name: "not json", file: `name: paper`, wantErr: true},
+		{name: "bad theme", file: `[{"name": "paper", "text": "blurple"}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ThemesFileName)
+			if tt.file != "" {
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatalf("WriteFile() error = %v", err)
+				}
+			}
+			got, err := LoadThemes(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadThemes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if names := ThemeNames(got); names != tt.wantNames {
+				t.Errorf("ThemeNames() = %q, want %q", names, tt.wantNames)
+			}
+			if banner := foreground(got[0].Banner); banner != tt.wantBanner {
+				t.Errorf("Banner = %v, want %v", banner, tt.wantBanner)
+			}
+		})
+	}
+}
+
+func TestGame_nextTheme(t *testing.T) {
+	g := newLayoutGame(t, jumpLayout)
+	var names []string
+	for range g.themes {
+		pressKeys(t, g, "T")
+		names = append(names, g.theme.Name)
+	}
+	if got, want := strings.Join(names, ", "), "dark, space, high-contrast, classic"; got != want {
+		t.Errorf("themes = %s, want %s", got, want)
+	}
+}
+
+func TestGame_cellStyle(t *testing.T) {
+	g := newLayoutGame(t, jumpLayout)
+	g.board.Open(4, 0)
+	g.board.ToggleFlag(5, 0)
+	tests := []struct {
+		name string
+		x, y int
+		want tcell.Style
+	}{
+		{name: "closed", x: 0, y: 0, want: g.theme.Closed},
+		{name: "number", x: 4, y: 0, want: g.theme.Numbers[0]},
+		{name: "flag", x: 5, y: 0, want: g.theme.Flag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.cellStyle(tt.x, tt.y); got != tt.want {
+				t.Errorf("cellStyle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
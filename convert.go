@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/config"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/mbf"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/video"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	fs.Usage = func() {
+		fs.Output().Write([]byte("Usage: convert <from> <to>\n" +
+			"Converts a board layout or a replay, the formats are picked by the file extensions:\n" +
+			"  .mbf    minesweeper board format\n" +
+			"  .txt    text format, - for the standard input or output\n" +
+			"  .jsonl  recorded game\n" +
+			"  .avf    Minesweeper Arbiter video\n" +
+			"  .rmv    Vienna Minesweeper video\n" +
+			"Replays are converted between .jsonl, .avf and .rmv, the layouts are taken from any of them.\n"))
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("convert needs the source and the destination")
+	}
+
+	if isReplay(fs.Arg(1)) {
+		log, err := readReplay(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		return writeReplay(fs.Arg(1), log)
+	}
+	board, err := readLayout(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return writeLayout(fs.Arg(1), &board)
+}
+
+func isReplay(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".avf", ".rmv":
+		return true
+	}
+	return false
+}
+
+// readReplay reads a recorded game or a video of another minesweeper.
+func readReplay(path string) (replay.Log, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".jsonl" {
+		return replay.Read(path)
+	}
+	var decode func(io.Reader) (replay.Log, error)
+	switch ext {
+	case ".avf":
+		decode = video.DecodeAVF
+	case ".rmv":
+		decode = video.DecodeRMV
+	default:
+		return replay.Log{}, fmt.Errorf("%s isn't a replay, only .jsonl, .avf and .rmv files are", path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return replay.Log{}, err
+	}
+	defer f.Close()
+	return decode(f)
+}
+
+func writeReplay(path string, log replay.Log) error {
+	var buf bytes.Buffer
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".jsonl":
+		err = log.Write(&buf)
+	case ".avf":
+		err = video.EncodeAVF(&buf, log)
+	case ".rmv":
+		err = video.EncodeRMV(&buf, log)
+	default:
+		return unsupportedFormat(ext)
+	}
+	if err != nil {
+		return err
+	}
+	return config.WriteFileAtomic(path, buf.Bytes())
+}
+
+func readLayout(path string) (model.Board, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return model.Board{}, err
+		}
+		return model.ParseBoard(string(data))
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".mbf":
+		f, err := os.Open(path)
+		if err != nil {
+			return model.Board{}, err
+		}
+		defer f.Close()
+		return mbf.Decode(f)
+	case ".txt":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return model.Board{}, err
+		}
+		return model.ParseBoard(string(data))
+	case ".jsonl", ".avf", ".rmv":
+		log, err := readReplay(path)
+		if err != nil {
+			return model.Board{}, err
+		}
+		return log.Header.Board, nil
+	default:
+		return model.Board{}, unsupportedFormat(ext)
+	}
+}
+
+func writeLayout(path string, board *model.Board) error {
+	if path == "-" {
+		_, err := os.Stdout.WriteString(board.Layout())
+		return err
+	}
+	var buf bytes.Buffer
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".mbf":
+		if err := mbf.Encode(&buf, board); err != nil {
+			return err
+		}
+	case ".txt":
+		buf.WriteString(board.Layout())
+	default:
+		return unsupportedFormat(ext)
+	}
+	return config.WriteFileAtomic(path, buf.Bytes())
+}
+
+func unsupportedFormat(ext string) error {
+	return fmt.Errorf("unknown format %q", ext)
+}
@@ -0,0 +1,152 @@
+package bot
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Version is printed in the greeting, it's bumped on changes breaking the bots.
+const Version = 2
+
+// Session plays games for a bot: it reads one command per line and answers every command with one line,
+// except board which is followed by the snapshot rows.
+type Session struct {
+	newBoard    func() (model.Board, error)
+	board       model.Board
+	changed     map[model.Point]bool
+	unsubscribe func()
+	out         *bufio.Writer
+}
+
+// NewSession returns a session getting the board of every game from newBoard.
+func NewSession(newBoard func() (model.Board, error)) *Session {
+	return &Session{newBoard: newBoard}
+}
+
+// Run plays until quit or the end of the input.
+func (s *Session) Run(in io.Reader, out io.Writer) error {
+	s.out = bufio.NewWriter(out)
+	s.reply("galaxy_tramp bot %d", Version)
+	if err := s.newGame(); err != nil {
+		return err
+	}
+	sc := bufio.NewScanner(in)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if fields[0] == "quit" {
+			s.reply("bye")
+			break
+		}
+		if err := s.handle(fields[0], fields[1:]); err != nil {
+			return err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return s.out.Flush()
+}
+
+func (s *Session) reply(format string, args ...any) {
+	fmt.Fprintf(s.out, format+"\n", args...)
+}
+
+// handle runs the command, the returned errors are the ones of the output, bad commands are answered with error.
+func (s *Session) handle(command string, args []string) error {
+	switch command {
+	case "open", "flag", "chord":
+		s.play(command, args)
+	case "board":
+		s.reply("board %d", s.board.GetSize())
+		fmt.Fprint(s.out, s.board.Snapshot())
+	case "state":
+		state := fmt.Sprintf("state %s moves %d flags %d holes %d opened %d", s.board.GetState().String(), s.board.GetMoves(),
+			s.board.GetFlagsCount(), s.board.GetBlackHolesCount(), s.board.GetOpenedCount())
+		// the code gives the layout away, it's for replaying the finished games
+		if c, ok := s.board.Code(); ok && s.board.GetState() != model.InProgress {
+			state += " code " + c.String()
+		}
+		s.reply("%s", state)
+	case "new":
+		if err := s.newGame(); err != nil {
+			s.reply("error %v", err)
+		}
+	default:
+		s.reply("error unknown command %q", command)
+	}
+	return s.out.Flush()
+}
+
+func (s *Session) newGame() error {
+	board, err := s.newBoard()
+	if err != nil {
+		return err
+	}
+	if s.unsubscribe != nil {
+		s.unsubscribe()
+	}
+	s.board = board
+	s.unsubscribe = s.board.Subscribe(s.onEvent)
+	s.reply("game %d %d", s.board.GetSize(), s.board.GetBlackHolesCount())
+	return s.out.Flush()
+}
+
+func (s *Session) onEvent(e model.Event) {
+	switch e := e.(type) {
+	case model.CellsOpened:
+		for _, p := range e.Cells {
+			s.changed[p] = true
+		}
+	case model.CellFlagged:
+		s.changed[model.NewPoint(e.X, e.Y)] = true
+	}
+}
+
+// play answers ok with the state and the changed cells as x,y=symbol in the snapshot symbols.
+func (s *Session) play(command string, args []string) {
+	if len(args) != 2 {
+		s.reply("error %s takes x y", command)
+		return
+	}
+	x, errX := strconv.Atoi(args[0])
+	y, errY := strconv.Atoi(args[1])
+	size := s.board.GetSize()
+	if errX != nil || errY != nil || x < 0 || x >= size || y < 0 || y >= size {
+		s.reply("error %s %s is outside of the board, x and y go from 0 to %d", args[0], args[1], size-1)
+		return
+	}
+	if s.board.GetState() != model.InProgress {
+		s.reply("error game is %s, send new for another one", s.board.GetState().String())
+		return
+	}
+
+	s.changed = map[model.Point]bool{}
+	switch command {
+	case "open":
+		s.board.Open(x, y)
+	case "flag":
+		s.board.ToggleFlag(x, y)
+	case "chord":
+		s.board.Chord(x, y)
+	}
+	cells := make([]model.Point, 0, len(s.changed))
+	for p := range s.changed {
+		cells = append(cells, p)
+	}
+	model.SortPoints(cells)
+
+	var sb strings.Builder
+	sb.WriteString("ok " + s.board.GetState().String())
+	snapshot := s.board.Snapshot()
+	for _, p := range cells {
+		fmt.Fprintf(&sb, " %d,%d=%c", p.X(), p.Y(), snapshot.Get(p.X(), p.Y()).Symbol())
+	}
+	s.reply("%s", sb.String())
+}
@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"bytes"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"strings"
+	"testing"
+)
+
+const layout = `
+	* 1 0 0
+	1 1 0 0
+	1 1 1 1
+	* 1 1 *
+	`
+
+func TestSession_Run(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands string
+		want     string
+	}{
+		{
+			name:     "cascade",
+			commands: "open 2 0\nstate\n",
+			want: "ok in_progress 1,0=1 2,0=0 3,0=0 1,1=1 2,1=0 3,1=0 1,2=1 2,2=1 3,2=1\n" +
+				"state in_progress moves 1 flags 0 holes 3 opened 9\n",
+		},
+		{
+			name:     "flag and board",
+			commands: "flag 0 0\nboard\nflag 0 0\n",
+			want:     "ok in_progress 0,0=F\nboard 4\nF ? ? ?\n? ? ? ?\n? ? ? ?\n? ? ? ?\nok in_progress 0,0=?\n",
+		},
+		{
+			name:     "lost",
+			commands: "open 3 3\nopen 0 1\n",
+			want:     "ok lost 3,3=*\nerror game is lost, send new for another one\n",
+		},
+		{
+			name:     "won and new game",
+			commands: "open 2 0\nchord 1 2\nflag 0 3\nchord 1 2\nnew\n",
+			want:     "ok in_progress 1,0=1 2,0=0 3,0=0 1,1=1 2,1=0 3,1=0 1,2=1 2,2=1 3,2=1\nok in_progress\nok in_progress 0,3=F\nok won 0,1=1 0,2=1 1,3=1 2,3=1\ngame 4 3\n",
+		},
+		{
+			name:     "errors",
+			commands: "open 4 0\nopen 1\njump 1 1\n# comment\n\nquit\nopen 0 0\n",
+			want:     "error 4 0 is outside of the board, x and y go from 0 to 3\nerror open takes x y\nerror unknown command \"jump\"\nbye\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSession(func() (model.Board, error) { return model.ParseLayout(layout) })
+			var out bytes.Buffer
+			if err := s.Run(strings.NewReader(tt.commands), &out); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			want := "galaxy_tramp bot 2\ngame 4 3\n" + tt.want
+			if out.String() != want {
+				t.Errorf("Run() =\n%s\nwant:\n%s", out.String(), want)
+			}
+		})
+	}
+}
+
+func TestSession_codeAfterTheEnd(t *testing.T) {
+	// any cell ends the game with all but one cell being black holes
+	board, err := model.NewBoard(model.SeededCoordinatesProvider{Seed: 7}, 5, 24)
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+	code, _ := board.Code()
+	s := NewSession(func() (model.Board, error) { return board, nil })
+	var out bytes.Buffer
+	if err := s.Run(strings.NewReader("state\nopen 2 2\nstate\n"), &out); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Run() =\n%s\nwant 5 lines", out.String())
+	}
+	if lines[1] != "game 5 24" || strings.Contains(lines[2], "code") {
+		t.Errorf("Run() gives the code away before the end:\n%s", out.String())
+	}
+	if !strings.HasSuffix(lines[4], " code "+code.String()) {
+		t.Errorf("state after the end = %q, want the code %s", lines[4], code)
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const appDir = "galaxy_tramp"
+
+// DirEnv overrides the directory where scores, saves and settings are kept.
+const DirEnv = "GALAXY_TRAMP_HOME"
+
+// Dir returns the directory for the game files, creating it if needed.
+func Dir() (string, error) {
+	dir := os.Getenv(DirEnv)
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(base, appDir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Path returns the path of the named file inside Dir.
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// WriteFileAtomic replaces the file content via a temporary file, so readers never see a half written file.
+func WriteFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,52 @@
+// Package mbf reads and writes board layouts in the MBF format of the desktop minesweeper tools.
+//
+// An MBF file is the width and the height of the board as one byte each, the number of mines
+// as a big endian uint16, followed by the x and y of every mine as one byte each.
+package mbf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"io"
+)
+
+// Decode reads a layout, only square boards can be played.
+func Decode(r io.Reader) (model.Board, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return model.Board{}, fmt.Errorf("can't read mbf header: %w", err)
+	}
+	width, height := int(header[0]), int(header[1])
+	if width != height {
+		return model.Board{}, fmt.Errorf("board is %dx%d, only square boards are supported", width, height)
+	}
+	count := int(binary.BigEndian.Uint16(header[2:]))
+	mines := make([]byte, 2*count)
+	if _, err := io.ReadFull(r, mines); err != nil {
+		return model.Board{}, fmt.Errorf("can't read %d mines: %w", count, err)
+	}
+
+	blackHoles := make([]model.Point, 0, count)
+	for i := 0; i < count; i++ {
+		blackHoles = append(blackHoles, model.NewPoint(int(mines[2*i]), int(mines[2*i+1])))
+	}
+	board, err := model.NewBoardFromLayout(width, blackHoles)
+	if err != nil {
+		return model.Board{}, fmt.Errorf("invalid mbf layout: %w", err)
+	}
+	return board, nil
+}
+
+// Encode writes the layout of the board, opened cells and flags aren't part of the format.
+func Encode(w io.Writer, b *model.Board) error {
+	blackHoles := b.GetBlackHoles()
+	data := make([]byte, 4, 4+2*len(blackHoles))
+	data[0], data[1] = byte(b.GetSize()), byte(b.GetSize())
+	binary.BigEndian.PutUint16(data[2:], uint16(len(blackHoles)))
+	for _, p := range blackHoles {
+		data = append(data, byte(p.X()), byte(p.Y()))
+	}
+	_, err := w.Write(data)
+	return err
+}
@@ -0,0 +1,72 @@
+package mbf
+
+import (
+	"bytes"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	board, err := model.ParseLayout(`
+		* 2 0 0
+		* 2 1 1
+		2 2 2 *
+		1 * 2 1
+		`)
+	if err != nil {
+		t.Fatalf("ParseLayout() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, &board); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	want := []byte{4, 4, 0, 4, 0, 0, 0, 1, 3, 2, 1, 3}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Encode() = %v, want %v", buf.Bytes(), want)
+	}
+
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.Layout() != board.Layout() {
+		t.Errorf("Decode():\n%s\nwant:\n%s", decoded.Layout(), board.Layout())
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		errorMessage string
+	}{
+		{
+			name:         "not square",
+			data:         []byte{30, 16, 0, 1, 0, 0},
+			errorMessage: "board is 30x16, only square boards are supported",
+		},
+		{
+			name:         "truncated",
+			data:         []byte{8, 8, 0, 2, 0, 0, 1},
+			errorMessage: "can't read 2 mines: unexpected EOF",
+		},
+		{
+			name:         "outside",
+			data:         []byte{8, 8, 0, 1, 8, 0},
+			errorMessage: "invalid mbf layout: black hole 8,0 is outside of the 8x8 board",
+		},
+		{
+			name:         "duplicate",
+			data:         []byte{8, 8, 0, 2, 1, 1, 1, 1},
+			errorMessage: "invalid mbf layout: black hole 1,1 is listed twice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(bytes.NewReader(tt.data))
+			if err == nil || err.Error() != tt.errorMessage {
+				t.Errorf("Decode() error = %v, want %s", err, tt.errorMessage)
+			}
+		})
+	}
+}
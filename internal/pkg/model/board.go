@@ -1,6 +1,10 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"time"
+)
 
 type State int
 
@@ -10,13 +14,46 @@ const (
 	Lost             = iota
 )
 
+var stateNames = map[State]string{
+	InProgress: "in_progress",
+	Won:        "won",
+	Lost:       "lost",
+}
+
+// String returns the name of the state used by the bot, the HTTP API, the page and the races.
+func (s State) String() string {
+	return stateNames[s]
+}
+
 type Board struct {
 	cells                        [][]cell
 	size                         int
 	state                        State
 	closedNonBlackHoleCellsCount int
+	moves                        int
+	blackHolesCount              int
+	flagsCount                   int
+	seed                         int64
+	generator                    Generator
+	lostAt                       Point
+	startedAt                    time.Time
+	finishedAt                   time.Time
+	pausedAt                     time.Time
+	practice                     bool
+	undone                       bool
+	hinted                       bool
+	reloaded                     bool
+	history                      []change
+	future                       []change
+	// journal collects the cells opened by the action in progress
+	journal      *[]Point
+	listeners    map[int]Listener
+	lastListener int
 }
 
+// now is replaced in tests to control the game timer
+var now = time.Now
+
 func (b *Board) GetSize() int {
 	return b.size
 }
@@ -25,11 +62,74 @@ func (b *Board) GetState() State {
 	return b.state
 }
 
+// GetMoves returns the number of opens that hit a closed cell.
+func (b *Board) GetMoves() int {
+	return b.moves
+}
+
+// GetElapsed returns time since the first move, the timer stops when the game is over.
+func (b *Board) GetElapsed() time.Duration {
+	switch {
+	case b.startedAt.IsZero():
+		return 0
+	case b.state != InProgress:
+		return b.finishedAt.Sub(b.startedAt)
+	case b.IsPaused():
+		return b.pausedAt.Sub(b.startedAt)
+	default:
+		return now().Sub(b.startedAt)
+	}
+}
+
+// Pause stops the timer of a started game, nothing can be opened or flagged until Resume.
+func (b *Board) Pause() {
+	if b.state == InProgress && !b.startedAt.IsZero() && !b.IsPaused() {
+		b.pausedAt = now()
+	}
+}
+
+func (b *Board) Resume() {
+	if b.IsPaused() {
+		b.startedAt = b.startedAt.Add(now().Sub(b.pausedAt))
+		b.pausedAt = time.Time{}
+	}
+}
+
+func (b *Board) IsPaused() bool {
+	return !b.pausedAt.IsZero()
+}
+
+func (b *Board) GetBlackHolesCount() int {
+	return b.blackHolesCount
+}
+
+// GetOpenedCount returns the number of opened cells that aren't black holes.
+func (b *Board) GetOpenedCount() int {
+	return b.size*b.size - b.blackHolesCount - b.closedNonBlackHoleCellsCount
+}
+
+// GetLostAt returns the black hole that ended the game, ok is false if the game wasn't lost.
+func (b *Board) GetLostAt() (x, y int, ok bool) {
+	if b.state != Lost {
+		return 0, 0, false
+	}
+	return b.lostAt.x, b.lostAt.y, true
+}
+
+// GetSeed returns the seed the black holes were placed with, 0 for boards not made from a seed.
+func (b *Board) GetSeed() int64 {
+	return b.seed
+}
+
 func (b *Board) IsOpened(x, y int) bool {
 	return b.cells[x][y].opened
 }
 
 func (b *Board) Open(x, y int) {
+	b.do(openAction, x, y)
+}
+
+func (b *Board) openAt(x, y int) {
 	// TODO: check if it's the first opened cell and rebuild board in case of we hit black hole.
 	// TODO: It should not be possible to loose with the first move
 
@@ -37,18 +137,106 @@ func (b *Board) Open(x, y int) {
 		return
 	}
 	c := &b.cells[x][y]
-	if c.opened {
+	if c.opened || c.flagged {
 		return
 	}
-	if c.blackHole {
-		c.opened = true
-		b.state = Lost
+	b.countMove()
+	b.open(x, y)
+}
+
+// Chord opens all not flagged neighbours of an opened number once it has as many flags around.
+func (b *Board) Chord(x, y int) {
+	b.do(chordAction, x, y)
+}
+
+func (b *Board) chordAt(x, y int) {
+	if outsideOfBoard(x, y, b.size) || b.state != InProgress {
+		return
+	}
+	c := b.cells[x][y]
+	if !c.opened || c.blackHole || c.neighboursCount == 0 {
+		return
+	}
+	var flags int
+	var closed []Point
+	forEachNeighbour(b.size, x, y, func(nx, ny int) {
+		switch n := b.cells[nx][ny]; {
+		case n.flagged:
+			flags++
+		case !n.opened:
+			closed = append(closed, Point{x: nx, y: ny})
+		}
+	})
+	if flags != c.neighboursCount || len(closed) == 0 {
+		return
+	}
+	b.countMove()
+	for _, p := range closed {
+		if b.state != InProgress {
+			return
+		}
+		b.open(p.x, p.y)
+	}
+}
+
+func (b *Board) countMove() {
+	if b.state == InProgress {
+		b.moves++
+		if b.moves == 1 {
+			b.startedAt = now()
+		}
+	}
+}
+
+func (b *Board) open(x, y int) {
+	if b.cells[x][y].blackHole {
+		b.cells[x][y].opened = true
+		b.log(x, y)
+		b.lostAt = Point{x: x, y: y}
+		b.finish(Lost)
 		return
 	}
 	b.openCell(x, y)
 	if b.closedNonBlackHoleCellsCount == 0 {
-		b.state = Won
+		b.finish(Won)
+	}
+}
+
+func (b *Board) finish(state State) {
+	if b.state == InProgress {
+		b.finishedAt = now()
+	}
+	b.state = state
+}
+
+// ToggleFlag marks a closed cell as a supposed black hole or removes the mark.
+func (b *Board) ToggleFlag(x, y int) {
+	b.do(flagAction, x, y)
+}
+
+func (b *Board) toggleFlagAt(x, y int) bool {
+	if outsideOfBoard(x, y, b.size) || b.state != InProgress {
+		return false
+	}
+	c := &b.cells[x][y]
+	if c.opened {
+		return false
+	}
+	c.flagged = !c.flagged
+	if c.flagged {
+		b.flagsCount++
+	} else {
+		b.flagsCount--
 	}
+	return true
+}
+
+func (b *Board) IsFlagged(x, y int) bool {
+	return b.cells[x][y].flagged
+}
+
+func (b *Board) GetFlagsCount() int {
+	return b.flagsCount
 }
 
 func (b *Board) IsBlackHole(x, y int) bool {
@@ -64,10 +252,12 @@ func outsideOfBoard(x, y, size int) bool {
 }
 
 func (b *Board) openCell(x, y int) {
-	if outsideOfBoard(x, y, b.size) || b.cells[x][y].opened {
+	// the cascade stops at the flags, the player removes them to open the cells
+	if outsideOfBoard(x, y, b.size) || b.cells[x][y].opened || b.cells[x][y].flagged {
 		return
 	}
 	b.cells[x][y].opened = true
+	b.log(x, y)
 	b.closedNonBlackHoleCellsCount--
 	if b.cells[x][y].neighboursCount == 0 {
 		b.openCell(x-1, y)
@@ -87,10 +277,58 @@ type Point struct {
 	y int
 }
 
+func NewPoint(x, y int) Point {
+	return Point{x: x, y: y}
+}
+
+func (p Point) X() int {
+	return p.x
+}
+
+func (p Point) Y() int {
+	return p.y
+}
+
+// SortPoints sorts the points row by row, like GetBlackHoles returns them.
+func SortPoints(points []Point) {
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].y != points[j].y {
+			return points[i].y < points[j].y
+		}
+		return points[i].x < points[j].x
+	})
+}
+
 type CoordinatesProvider interface {
 	coordinates(size, count int) ([]Point, error)
 }
 
+// seeded is implemented by providers placing the black holes from a seed.
+type seeded interface {
+	getSeed() int64
+	getGenerator() Generator
+}
+
+// layout places the black holes exactly at the given points, every point has to be on the board once.
+type layout []Point
+
+func (l layout) coordinates(size, count int) ([]Point, error) {
+	if len(l) != count {
+		return nil, fmt.Errorf("layout has %d black holes, want %d", len(l), count)
+	}
+	seen := make(map[Point]bool, len(l))
+	for _, p := range l {
+		if outsideOfBoard(p.x, p.y, size) {
+			return nil, fmt.Errorf("black hole %d,%d is outside of the %dx%d board", p.x, p.y, size, size)
+		}
+		if seen[p] {
+			return nil, fmt.Errorf("black hole %d,%d is listed twice", p.x, p.y)
+		}
+		seen[p] = true
+	}
+	return l, nil
+}
+
 func NewBoard(cp CoordinatesProvider, size, blackHoleCount int) (Board, error) {
 	if size <= 0 {
 		return Board{}, fmt.Errorf("size should be greater then 0")
@@ -111,6 +349,11 @@ func NewBoard(cp CoordinatesProvider, size, blackHoleCount int) (Board, error) {
 	if err != nil {
 		return Board{}, err
 	}
+	var seed int64
+	var generator Generator
+	if s, ok := cp.(seeded); ok {
+		seed, generator = s.getSeed(), s.getGenerator()
+	}
 
 	for _, p := range blackHoleCoordinates {
 		cells[p.x][p.y].turnToBlackHole()
@@ -130,9 +373,44 @@ func NewBoard(cp CoordinatesProvider, size, blackHoleCount int) (Board, error) {
 		size:                         size,
 		state:                        InProgress,
 		closedNonBlackHoleCellsCount: size*size - blackHoleCount,
+		blackHolesCount:              blackHoleCount,
+		seed:                         seed,
+		generator:                    generator,
 	}, nil
 }
 
+// NewBoardFromLayout creates a board with black holes exactly at the given points.
+func NewBoardFromLayout(size int, blackHoles []Point) (Board, error) {
+	return NewBoard(layout(blackHoles), size, len(blackHoles))
+}
+
+// GetBlackHoles returns the black hole positions row by row.
+func (b *Board) GetBlackHoles() []Point {
+	var points []Point
+	for y := 0; y < b.size; y++ {
+		for x := 0; x < b.size; x++ {
+			if b.cells[x][y].blackHole {
+				points = append(points, Point{x: x, y: y})
+			}
+		}
+	}
+	return points
+}
+
+// Clone returns an independent copy of the board, listeners aren't copied.
+func (b *Board) Clone() Board {
+	c := *b
+	c.cells = make([][]cell, len(b.cells))
+	for i := range b.cells {
+		c.cells[i] = append([]cell(nil), b.cells[i]...)
+	}
+	c.history = append([]change(nil), b.history...)
+	c.future = append([]change(nil), b.future...)
+	c.journal = nil
+	c.listeners = nil
+	return c
+}
+
 func markAsBlackHoleNeighbour(cells [][]cell, x, y int) {
 	if outsideOfBoard(x, y, len(cells)) {
 		return
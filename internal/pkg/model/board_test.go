@@ -368,3 +368,198 @@ func TestBoard_Open(t *testing.T) {
 		})
 	}
 }
+
+func TestBoard_ToggleFlag(t *testing.T) {
+	board, err := NewBoard(fixedCoordinatesProvider{points: [][]int{{1, 0}, {0, 2}}}, 3, 2)
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+
+	board.ToggleFlag(1, 0)
+	board.ToggleFlag(0, 0)
+	board.Open(0, 0)
+	if board.IsOpened(0, 0) || board.GetMoves() != 0 {
+		t.Errorf("flagged cell was opened")
+	}
+	board.ToggleFlag(0, 0)
+	board.Open(0, 0)
+	board.ToggleFlag(0, 0)
+	board.ToggleFlag(10, 5)
+	if !board.IsOpened(0, 0) || board.IsFlagged(0, 0) {
+		t.Errorf("unflagged cell wasn't opened or opened cell was flagged")
+	}
+	if !board.IsFlagged(1, 0) || board.GetFlagsCount() != 1 {
+		t.Errorf("GetFlagsCount() = %d, want 1", board.GetFlagsCount())
+	}
+}
+
+func TestBoard_OpenAroundFlag(t *testing.T) {
+	board, err := ParseLayout(`
+		0 0 0 1 *
+		0 0 0 1 1
+		0 0 0 0 0
+		1 1 0 0 0
+		* 1 0 0 0
+		`)
+	if err != nil {
+		t.Fatalf("ParseLayout() error = %v", err)
+	}
+	board.ToggleFlag(1, 1)
+	board.Open(4, 4)
+	if board.IsOpened(1, 1) || !board.IsFlagged(1, 1) || board.GetFlagsCount() != 1 {
+		t.Errorf("flag inside of the opening: opened %v, flagged %v, flags %d, want a closed flag",
+			board.IsOpened(1, 1), board.IsFlagged(1, 1), board.GetFlagsCount())
+	}
+	if !board.IsOpened(0, 0) || board.GetState() != InProgress {
+		t.Errorf("opening stopped at the flag: 0,0 opened %v, state %v", board.IsOpened(0, 0), board.GetState())
+	}
+	board.ToggleFlag(1, 1)
+	board.Open(1, 1)
+	if board.GetState() != Won || board.GetFlagsCount() != 0 {
+		t.Errorf("state %v, flags %d after opening the unflagged cell, want won without flags", board.GetState(), board.GetFlagsCount())
+	}
+}
+
+func TestBoard_Chord(t *testing.T) {
+	tests := []struct {
+		name            string
+		opened          [][]int
+		flagged         [][]int
+		chord           []int
+		wantState       State
+		wantMoves       int
+		wantOpenedBoard string
+	}{
+		{
+			name:      "flags match the number",
+			opened:    [][]int{{0, 0}},
+			flagged:   [][]int{{1, 0}},
+			chord:     []int{0, 0},
+			wantState: InProgress,
+			wantMoves: 2,
+			wantOpenedBoard: `
+				1 ? ?
+				2 2 ?
+				? ? ?
+				`,
+		},
+		{
+			name:      "wrong flag",
+			opened:    [][]int{{0, 0}},
+			flagged:   [][]int{{0, 1}},
+			chord:     []int{0, 0},
+			wantState: Lost,
+			wantMoves: 2,
+			wantOpenedBoard: `
+				1 * ?
+				? ? ?
+				? ? ?
+				`,
+		},
+		{
+			name:      "not enough flags",
+			opened:    [][]int{{1, 1}},
+			flagged:   [][]int{{1, 0}},
+			chord:     []int{1, 1},
+			wantState: InProgress,
+			wantMoves: 1,
+			wantOpenedBoard: `
+				? ? ?
+				? 2 ?
+				? ? ?
+				`,
+		},
+		{
+			name:      "closed cell",
+			flagged:   [][]int{{1, 0}},
+			chord:     []int{0, 0},
+			wantState: InProgress,
+			wantMoves: 0,
+			wantOpenedBoard: `
+				? ? ?
+				? ? ?
+				? ? ?
+				`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := NewBoard(fixedCoordinatesProvider{points: [][]int{{1, 0}, {0, 2}}}, 3, 2)
+			if err != nil {
+				t.Fatalf("NewBoard() error = %v", err)
+			}
+			for _, p := range tt.opened {
+				board.Open(p[0], p[1])
+			}
+			for _, p := range tt.flagged {
+				board.ToggleFlag(p[0], p[1])
+			}
+			board.Chord(tt.chord[0], tt.chord[1])
+
+			actualOpened := boardToString(board, true)
+			if !equalIgnoreSpaces(actualOpened, tt.wantOpenedBoard) {
+				t.Errorf("Got:\n%s\nWant:\n%s", actualOpened, tt.wantOpenedBoard)
+			}
+			if board.GetState() != tt.wantState || board.GetMoves() != tt.wantMoves {
+				t.Errorf("state %v, moves %d, want %v, %d", board.GetState(), board.GetMoves(), tt.wantState, tt.wantMoves)
+			}
+		})
+	}
+}
+
+func TestState_String(t *testing.T) {
+	tests := []struct {
+		state State
+		want  string
+	}{
+		{state: InProgress, want: "in_progress"},
+		{state: Won, want: "won"},
+		{state: Lost, want: "lost"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.state.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortPoints(t *testing.T) {
+	points := []Point{{x: 2, y: 1}, {x: 0, y: 2}, {x: 1, y: 1}, {x: 3, y: 0}}
+	SortPoints(points)
+	if got, want := fmt.Sprint(points), "[{3 0} {1 1} {2 1} {0 2}]"; got != want {
+		t.Errorf("SortPoints() = %s, want %s", got, want)
+	}
+}
+
+func TestNewBoardFromLayout(t *testing.T) {
+	tests := []struct {
+		name         string
+		size         int
+		blackHoles   []Point
+		errorMessage string
+	}{
+		{name: "layout", size: 3, blackHoles: []Point{{x: 1, y: 0}, {x: 0, y: 2}}},
+		{name: "black hole twice", size: 3, blackHoles: []Point{{x: 1, y: 0}, {x: 0, y: 2}, {x: 1, y: 0}}, errorMessage: "black hole 1,0 is listed twice"},
+		{name: "black hole outside", size: 3, blackHoles: []Point{{x: 1, y: 3}}, errorMessage: "black hole 1,3 is outside of the 3x3 board"},
+		{name: "negative black hole", size: 3, blackHoles: []Point{{x: -1, y: 0}}, errorMessage: "black hole -1,0 is outside of the 3x3 board"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := NewBoardFromLayout(tt.size, tt.blackHoles)
+			if tt.errorMessage != "" {
+				if err == nil || err.Error() != tt.errorMessage {
+					t.Errorf("NewBoardFromLayout() error = %v, want %v", err, tt.errorMessage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewBoardFromLayout() error = %v", err)
+			}
+			if got := board.GetBlackHolesCount(); got != len(tt.blackHoles) {
+				t.Errorf("GetBlackHolesCount() = %d, want %d", got, len(tt.blackHoles))
+			}
+		})
+	}
+}
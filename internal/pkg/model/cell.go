@@ -2,6 +2,7 @@ package model
 
 type cell struct {
 	opened          bool
+	flagged         bool
 	blackHole       bool
 	neighboursCount int
 }
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"strings"
+)
+
+// codeVersion is bumped when the layout of the code itself changes.
+const codeVersion = 1
+
+// Generator is the version of the algorithm placing the black holes from a seed.
+// Codes keep the generator they were made with, so old codes give the same boards.
+type Generator int
+
+const (
+	// GeneratorV1 shuffles the cells with math/rand.
+	GeneratorV1 Generator = 1
+	// GeneratorV2 shuffles the cells with its own SplitMix64.
+	GeneratorV2 Generator = 2
+)
+
+// Topology tells which cells are neighbours.
+type Topology int
+
+const (
+	// Square is the grid where every cell touches its 8 neighbours.
+	Square Topology = 0
+)
+
+// codeEncoding is the Crockford alphabet, it has no letters looking like digits.
+var codeEncoding = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+const codeGroup = 5
+
+// Code identifies a board generated from a seed, anyone can play the same board from it.
+type Code struct {
+	Generator       Generator
+	Topology        Topology
+	Size            int
+	BlackHolesCount int
+	Seed            int64
+}
+
+// Code returns the code of the board, ok is false for boards not generated from a seed.
+func (b *Board) Code() (c Code, ok bool) {
+	if b.seed == 0 {
+		return Code{}, false
+	}
+	return Code{Generator: b.generator, Topology: Square, Size: b.size, BlackHolesCount: b.blackHolesCount, Seed: b.seed}, true
+}
+
+// NewBoard generates the board of the code.
+func (c Code) NewBoard() (Board, error) {
+	if c.Topology != Square {
+		return Board{}, fmt.Errorf("unsupported topology %d", c.Topology)
+	}
+	switch c.Generator {
+	case GeneratorV1, GeneratorV2:
+		return NewBoard(SeededCoordinatesProvider{Seed: c.Seed, Generator: c.Generator}, c.Size, c.BlackHolesCount)
+	}
+	return Board{}, fmt.Errorf("unsupported generator %d", c.Generator)
+}
+
+// String returns the code in groups of letters and digits, i.e. 2400G-2QP06-NGJ.
+func (c Code) String() string {
+	data := []byte{codeVersion<<4 | byte(c.Generator), byte(c.Topology), byte(c.Size)}
+	data = binary.AppendUvarint(data, uint64(c.BlackHolesCount))
+	data = binary.AppendVarint(data, c.Seed)
+	// 2 check bytes, 1 would let a typo in 256 through
+	data = binary.BigEndian.AppendUint16(data, uint16(crc32.ChecksumIEEE(data)))
+
+	s := codeEncoding.EncodeToString(data)
+	var groups []string
+	for len(s) > codeGroup {
+		groups = append(groups, s[:codeGroup])
+		s = s[codeGroup:]
+	}
+	return strings.Join(append(groups, s), "-")
+}
+
+// ParseCode reads a code printed by Code.String, dashes, spaces and the case don't matter.
+func ParseCode(s string) (Code, error) {
+	s = strings.ToUpper(strings.Join(strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == ' ' }), ""))
+	// Crockford base32 reads the letters looking like digits as those digits
+	s = strings.NewReplacer("O", "0", "I", "1", "L", "1").Replace(s)
+	data, err := codeEncoding.DecodeString(s)
+	if err != nil || len(data) < 7 {
+		return Code{}, fmt.Errorf("invalid board code")
+	}
+	check := len(data) - 2
+	if binary.BigEndian.Uint16(data[check:]) != uint16(crc32.ChecksumIEEE(data[:check])) {
+		return Code{}, fmt.Errorf("invalid board code, check it for typos")
+	}
+	if v := int(data[0] >> 4); v != codeVersion {
+		return Code{}, fmt.Errorf("unsupported board code version %d", v)
+	}
+
+	c := Code{Generator: Generator(data[0] & 0x0f), Topology: Topology(data[1]), Size: int(data[2])}
+	rest := data[3:check]
+	holes, n := binary.Uvarint(rest)
+	if n <= 0 {
+		return Code{}, fmt.Errorf("invalid board code")
+	}
+	seed, m := binary.Varint(rest[n:])
+	if m <= 0 || n+m != len(rest) {
+		return Code{}, fmt.Errorf("invalid board code")
+	}
+	c.BlackHolesCount, c.Seed = int(holes), seed
+	return c, nil
+}
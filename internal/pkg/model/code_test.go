@@ -0,0 +1,147 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCode_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		code Code
+		want string
+	}{
+		{
+			name: "easy",
+			code: Code{Generator: GeneratorV1, Topology: Square, Size: 8, BlackHolesCount: 10, Seed: SEED},
+			want: "2400G-2QP06-NGJ",
+		},
+		{
+			name: "hard with a time seed",
+			code: Code{Generator: GeneratorV1, Topology: Square, Size: 24, BlackHolesCount: 99, Seed: 1677664800000},
+			want: "2401G-RW0PK-NCKMV-1HZ5G",
+		},
+		{
+			name: "negative seed",
+			code: Code{Generator: GeneratorV1, Topology: Square, Size: 50, BlackHolesCount: 2500, Seed: -42},
+			want: "24035-H0KAC-ZZE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.code.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+			got, err := ParseCode(tt.want)
+			if err != nil {
+				t.Fatalf("ParseCode(%s) error = %v", tt.want, err)
+			}
+			if got != tt.code {
+				t.Errorf("ParseCode(%s) = %+v, want %+v", tt.want, got, tt.code)
+			}
+		})
+	}
+}
+
+// The boards of published codes must never change, new generators get new versions.
+func TestCode_NewBoard(t *testing.T) {
+	code, err := ParseCode("2400g 2qpo6 ngj")
+	if err != nil {
+		t.Fatalf("ParseCode() error = %v", err)
+	}
+	board, err := code.NewBoard()
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+	want := `
+		0 1 * 1 0 0 0 0
+		1 2 2 1 0 0 1 1
+		1 * 1 0 0 0 1 *
+		2 2 3 1 1 0 1 1
+		2 * 4 * 2 1 0 0
+		2 * * 4 * 1 0 0
+		1 3 * 3 2 2 1 0
+		0 1 1 1 1 * 1 0
+		`
+	if !equalIgnoreSpaces(board.Layout(), want) {
+		t.Errorf("NewBoard():\n%s\nwant:\n%s", board.Layout(), want)
+	}
+	if got, ok := board.Code(); !ok || got != code {
+		t.Errorf("Code() = %+v, %v, want %+v", got, ok, code)
+	}
+}
+
+func TestParseCode_Errors(t *testing.T) {
+	tests := []struct {
+		code         string
+		errorMessage string
+	}{
+		{code: "2400G-2QP07-NGJ", errorMessage: "invalid board code, check it for typos"},
+		{code: "2400G-2QP06-NHJ", errorMessage: "invalid board code, check it for typos"},
+		{code: "2400G-2QP06-NG", errorMessage: "invalid board code, check it for typos"},
+		{code: "2400G", errorMessage: "invalid board code"},
+		{code: "2400G-2QP0U-NGJ", errorMessage: "invalid board code"},
+		{code: "241GG-2QP07-8XJ", errorMessage: "unsupported topology 3"},
+		{code: "2W00G-2QP05-418", errorMessage: "unsupported generator 7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			code, err := ParseCode(tt.code)
+			if err == nil {
+				_, err = code.NewBoard()
+			}
+			if err == nil || err.Error() != tt.errorMessage {
+				t.Errorf("ParseCode(%s) error = %v, want %s", tt.code, err, tt.errorMessage)
+			}
+		})
+	}
+}
+
+func TestDailyCode(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		want int64
+	}{
+		{
+			name: "utc",
+			at:   time.Date(2023, 3, 1, 23, 59, 0, 0, time.UTC),
+			want: 20230301,
+		},
+		{
+			name: "ahead of utc",
+			at:   time.Date(2023, 3, 2, 1, 0, 0, 0, time.FixedZone("CET", 2*60*60)),
+			want: 20230301,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := DailyCode(tt.at)
+			if code.Seed != tt.want || code.Size != Daily.Size || code.BlackHolesCount != Daily.BlackHolesCount {
+				t.Errorf("DailyCode() = %+v, want seed %d", code, tt.want)
+			}
+			date, ok := DailyDate(code.Seed)
+			if !ok || date.Format("2006-01-02") != tt.at.UTC().Format("2006-01-02") {
+				t.Errorf("DailyDate(%d) = %v, %v", code.Seed, date, ok)
+			}
+		})
+	}
+	if _, ok := DailyDate(1677664800000); ok {
+		t.Errorf("DailyDate() accepted a time seed")
+	}
+}
+
+// The daily boards stay the ones of the second generator whatever the latest generator is.
+func TestDailyCode_board(t *testing.T) {
+	board, err := DailyCode(time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)).NewBoard()
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+	want, err := NewBoard(SeededCoordinatesProvider{Seed: 20230301, Generator: GeneratorV2}, Daily.Size, Daily.BlackHolesCount)
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+	if board.Layout() != want.Layout() {
+		t.Errorf("daily board:\n%s\nwant:\n%s", board.Layout(), want.Layout())
+	}
+}
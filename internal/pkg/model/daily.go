@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Daily is the difficulty of the daily challenge, everyone gets the same board on the same UTC date.
+var Daily = Difficulty{Name: "daily", Size: 16, BlackHolesCount: 40}
+
+// DailyGenerator makes the daily boards. It's picked once and for all, a newer generator mustn't change
+// the boards of the days already played.
+const DailyGenerator = GeneratorV2
+
+// DailySeed returns the seed of the daily board of the UTC date of t, i.e. 20230301.
+func DailySeed(t time.Time) int64 {
+	y, m, d := t.UTC().Date()
+	return int64(y*10000 + int(m)*100 + d)
+}
+
+// DailyCode returns the code of the daily board of the UTC date of t.
+func DailyCode(t time.Time) Code {
+	return Code{Generator: DailyGenerator, Topology: Square, Size: Daily.Size, BlackHolesCount: Daily.BlackHolesCount, Seed: DailySeed(t)}
+}
+
+// DailyDate returns the date of the daily board made from the seed, ok is false if it isn't a daily seed.
+func DailyDate(seed int64) (date time.Time, ok bool) {
+	y, m, d := int(seed/10000), time.Month(seed/100%100), int(seed%100)
+	date = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if seed <= 0 || date.Year() != y || date.Month() != m || date.Day() != d {
+		return time.Time{}, false
+	}
+	return date, true
+}
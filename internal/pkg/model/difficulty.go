@@ -0,0 +1,64 @@
+package model
+
+import "fmt"
+
+type Difficulty struct {
+	Name            string `json:"name"`
+	Size            int    `json:"size"`
+	BlackHolesCount int    `json:"blackHolesCount"`
+}
+
+var (
+	Easy   = Difficulty{Name: "easy", Size: 8, BlackHolesCount: 10}
+	Medium = Difficulty{Name: "medium", Size: 16, BlackHolesCount: 40}
+	Hard   = Difficulty{Name: "hard", Size: 24, BlackHolesCount: 99}
+)
+
+var difficulties = []Difficulty{Easy, Medium, Hard}
+
+func Difficulties() []Difficulty {
+	return append([]Difficulty(nil), difficulties...)
+}
+
+func DifficultyByName(name string) (Difficulty, error) {
+	for _, d := range difficulties {
+		if d.Name == name {
+			return d, nil
+		}
+	}
+	return Difficulty{}, fmt.Errorf("unknown difficulty %q", name)
+}
+
+const Custom = "custom"
+
+// CustomDifficulty returns the preset with the same parameters if there is one.
+func CustomDifficulty(size, blackHolesCount int) Difficulty {
+	for _, d := range difficulties {
+		if d.Size == size && d.BlackHolesCount == blackHolesCount {
+			return d
+		}
+	}
+	return Difficulty{Name: Custom, Size: size, BlackHolesCount: blackHolesCount}
+}
+
+// Custom returns the difficulty with the size and the black holes count changed, zero keeps the one of d.
+func (d Difficulty) Custom(size, blackHolesCount int) Difficulty {
+	if size <= 0 && blackHolesCount <= 0 {
+		return d
+	}
+	if size <= 0 {
+		size = d.Size
+	}
+	if blackHolesCount <= 0 {
+		blackHolesCount = d.BlackHolesCount
+	}
+	return CustomDifficulty(size, blackHolesCount)
+}
+
+// Key identifies the difficulty in scores and statistics, custom boards are told apart by their parameters.
+func (d Difficulty) Key() string {
+	if d.Name != Custom {
+		return d.Name
+	}
+	return fmt.Sprintf("%s-%dx%d-%d", Custom, d.Size, d.Size, d.BlackHolesCount)
+}
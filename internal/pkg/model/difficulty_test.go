@@ -0,0 +1,29 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestDifficulty_Custom(t *testing.T) {
+	tests := []struct {
+		name            string
+		difficulty      Difficulty
+		size            int
+		blackHolesCount int
+		want            Difficulty
+	}{
+		{name: "unchanged", difficulty: Medium, want: Medium},
+		{name: "size", difficulty: Easy, size: 10, want: Difficulty{Name: Custom, Size: 10, BlackHolesCount: 10}},
+		{name: "black holes", difficulty: Easy, blackHolesCount: 12, want: Difficulty{Name: Custom, Size: 8, BlackHolesCount: 12}},
+		{name: "both", difficulty: Hard, size: 12, blackHolesCount: 20, want: Difficulty{Name: Custom, Size: 12, BlackHolesCount: 20}},
+		{name: "a preset", difficulty: Easy, size: 16, blackHolesCount: 40, want: Medium},
+		{name: "negative", difficulty: Easy, size: -1, blackHolesCount: -1, want: Easy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.difficulty.Custom(tt.size, tt.blackHolesCount); got != tt.want {
+				t.Errorf("Custom() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package model
+
+// Event is one of GameStarted, CellsOpened, CellsClosed, CellFlagged or StateChanged.
+type Event interface {
+	event()
+}
+
+// GameStarted is sent on the first move, when the timer starts.
+type GameStarted struct{}
+
+// CellsOpened is sent once per open or chord with all the cells it opened, including the cascade.
+type CellsOpened struct {
+	X     int
+	Y     int
+	Cells []Point
+}
+
+// CellsClosed is sent when an open is undone.
+type CellsClosed struct {
+	Cells []Point
+}
+
+type CellFlagged struct {
+	X       int
+	Y       int
+	Flagged bool
+}
+
+type StateChanged struct {
+	From State
+	To   State
+}
+
+func (GameStarted) event()  {}
+func (CellsOpened) event()  {}
+func (CellsClosed) event()  {}
+func (CellFlagged) event()  {}
+func (StateChanged) event() {}
+
+type Listener func(e Event)
+
+// Subscribe calls the listener synchronously on every change of the board, in the goroutine
+// changing the board. It returns the function removing the listener.
+func (b *Board) Subscribe(l Listener) func() {
+	if b.listeners == nil {
+		b.listeners = map[int]Listener{}
+	}
+	b.lastListener++
+	id := b.lastListener
+	b.listeners[id] = l
+	return func() { delete(b.listeners, id) }
+}
+
+// Notify sends the events to the channel. Sends block, so the receiver has to keep reading
+// or provide a big enough buffer. It returns the function stopping the notifications.
+func (b *Board) Notify(ch chan<- Event) func() {
+	return b.Subscribe(func(e Event) { ch <- e })
+}
+
+func (b *Board) emit(e Event) {
+	for _, l := range b.listeners {
+		l(e)
+	}
+}
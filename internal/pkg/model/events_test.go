@@ -0,0 +1,66 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBoard_Subscribe(t *testing.T) {
+	board, err := NewBoard(fixedCoordinatesProvider{points: [][]int{{1, 0}, {0, 2}}}, 3, 2)
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+	board.SetPractice(true)
+	var got []Event
+	unsubscribe := board.Subscribe(func(e Event) { got = append(got, e) })
+
+	board.Open(2, 2)
+	board.Open(2, 2)
+	board.ToggleFlag(1, 0)
+	board.Open(0, 2)
+	board.Undo()
+	unsubscribe()
+	board.Open(0, 0)
+
+	want := []Event{
+		GameStarted{},
+		CellsOpened{X: 2, Y: 2, Cells: []Point{{x: 2, y: 2}, {x: 1, y: 2}, {x: 2, y: 1}, {x: 1, y: 1}}},
+		CellFlagged{X: 1, Y: 0, Flagged: true},
+		CellsOpened{X: 0, Y: 2, Cells: []Point{{x: 0, y: 2}}},
+		StateChanged{From: InProgress, To: Lost},
+		CellsClosed{Cells: []Point{{x: 0, y: 2}}},
+		StateChanged{From: Lost, To: InProgress},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events:\n%+v\nwant:\n%+v", got, want)
+	}
+}
+
+func TestBoard_Notify(t *testing.T) {
+	board, err := NewBoard(fixedCoordinatesProvider{points: [][]int{{1, 0}, {0, 2}}}, 3, 2)
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+	ch := make(chan Event, 10)
+	stop := board.Notify(ch)
+	defer stop()
+
+	for _, p := range [][]int{{0, 0}, {2, 0}, {0, 1}, {2, 2}} {
+		board.Open(p[0], p[1])
+	}
+	close(ch)
+
+	var states []StateChanged
+	opened := 0
+	for e := range ch {
+		switch e := e.(type) {
+		case CellsOpened:
+			opened += len(e.Cells)
+		case StateChanged:
+			states = append(states, e)
+		}
+	}
+	if opened != 7 || !reflect.DeepEqual(states, []StateChanged{{From: InProgress, To: Won}}) {
+		t.Errorf("opened %d cells, state changes %+v, want 7 cells and a win", opened, states)
+	}
+}
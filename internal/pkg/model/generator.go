@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+	"math"
+)
+
+// LatestGenerator places the black holes of new boards.
+const LatestGenerator = GeneratorV2
+
+// SeededCoordinatesProvider places the black holes with a versioned generator. Unlike math/rand,
+// the generators are part of the game, so a seed gives the same board whatever Go version builds it.
+type SeededCoordinatesProvider struct {
+	Seed int64
+	// Generator is the version of the generator, zero stands for LatestGenerator.
+	Generator Generator
+}
+
+func (p SeededCoordinatesProvider) getSeed() int64 {
+	return p.Seed
+}
+
+func (p SeededCoordinatesProvider) getGenerator() Generator {
+	if p.Generator == 0 {
+		return LatestGenerator
+	}
+	return p.Generator
+}
+
+func (p SeededCoordinatesProvider) coordinates(size, count int) ([]Point, error) {
+	switch p.getGenerator() {
+	case GeneratorV1:
+		return RandomCoordinatesProvider{Seed: p.Seed}.coordinates(size, count)
+	case GeneratorV2:
+		if count > size*size {
+			return nil, fmt.Errorf("count should be less then or equal to board square (size*size)")
+		}
+		c := initCoordinates(size)
+		rnd := splitMix64{state: uint64(p.Seed)}
+		// a partial Fisher-Yates shuffle, only the first count cells are needed
+		for i := 0; i < count; i++ {
+			j := i + int(rnd.below(uint64(len(c)-i)))
+			c[i], c[j] = c[j], c[i]
+		}
+		return c[:count], nil
+	}
+	return nil, fmt.Errorf("unsupported generator %d", p.Generator)
+}
+
+// splitMix64 is the SplitMix64 generator by Sebastiano Vigna, it must never change.
+type splitMix64 struct {
+	state uint64
+}
+
+func (s *splitMix64) next() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// below returns a uniform number in [0, n).
+func (s *splitMix64) below(n uint64) uint64 {
+	// the values past the last whole multiple of n would make the low numbers more likely
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		if v := s.next(); v < limit {
+			return v % n
+		}
+	}
+}
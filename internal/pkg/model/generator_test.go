@@ -0,0 +1,124 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// The reference values of SplitMix64 for the seed 1234567.
+func TestSplitMix64(t *testing.T) {
+	want := []uint64{6457827717110365317, 3203168211198807973, 9817491932198370423, 4593380528125082431, 16408922859458223821}
+	s := splitMix64{state: 1234567}
+	for i, w := range want {
+		if got := s.next(); got != w {
+			t.Errorf("next() #%d = %d, want %d", i, got, w)
+		}
+	}
+}
+
+// The layouts of the seeds must never change, a change of the generator needs a new version.
+func TestSeededCoordinatesProvider_Golden(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		count int
+		seed  int64
+		want  string
+	}{
+		{
+			name:  "easy",
+			size:  8,
+			count: 10,
+			seed:  SEED,
+			want: `
+				0 0 0 0 0 1 * 2
+				0 0 0 1 1 2 2 *
+				0 0 0 1 * 3 3 2
+				1 2 2 2 2 * * 1
+				1 * * 2 2 2 2 1
+				1 2 3 * 1 0 0 0
+				0 0 1 1 1 1 2 2
+				0 0 0 0 0 1 * *
+				`,
+		},
+		{
+			name:  "negative seed",
+			size:  5,
+			count: 5,
+			seed:  -1,
+			want: `
+				* 1 0 0 0
+				3 3 1 0 0
+				* * 1 1 1
+				2 2 2 2 *
+				0 0 1 * 2
+				`,
+		},
+		{
+			name:  "daily",
+			size:  16,
+			count: 40,
+			seed:  20230301,
+			want: `
+				0 0 1 * 2 2 * * 2 1 0 0 0 0 0 0
+				0 0 1 1 2 * 3 3 * 2 1 0 0 1 1 1
+				0 0 0 1 2 2 1 2 3 * 1 1 1 2 * 1
+				0 0 0 1 * 2 1 1 * 2 1 1 * 2 1 1
+				1 1 1 1 2 * 1 2 3 4 2 2 1 1 0 0
+				2 * 1 1 3 4 3 2 * * * 1 0 1 1 1
+				* 3 2 2 * * * 3 3 3 2 2 1 2 * 1
+				2 * 1 3 * 5 3 * 1 0 1 2 * 3 2 2
+				2 3 3 3 * 2 1 2 2 1 1 * 2 2 * 1
+				2 * * 2 1 1 0 1 * 1 1 1 1 1 1 1
+				* 3 2 1 1 1 1 1 1 2 1 1 0 0 0 0
+				1 1 0 1 2 * 2 1 1 1 * 1 0 1 1 1
+				0 0 0 1 * 2 2 * 3 3 2 1 0 1 * 1
+				0 0 0 1 1 1 2 3 * * 1 0 0 1 1 1
+				1 1 1 0 0 0 1 * 3 2 1 0 0 0 0 0
+				1 * 1 0 0 0 1 1 1 0 0 0 0 0 0 0
+				`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := NewBoard(SeededCoordinatesProvider{Seed: tt.seed, Generator: GeneratorV2}, tt.size, tt.count)
+			if err != nil {
+				t.Fatalf("NewBoard() error = %v", err)
+			}
+			if !equalIgnoreSpaces(board.Layout(), tt.want) {
+				t.Errorf("NewBoard():\n%s\nwant:\n%s", board.Layout(), tt.want)
+			}
+			code, ok := board.Code()
+			if !ok || code.Generator != GeneratorV2 {
+				t.Errorf("Code() = %+v, %v, want generator %d", code, ok, GeneratorV2)
+			}
+		})
+	}
+}
+
+func TestBoard_GeneratorPersisted(t *testing.T) {
+	board, err := NewBoard(SeededCoordinatesProvider{Seed: SEED}, 8, 10)
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+	data, err := json.Marshal(board)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var restored Board
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if code, _ := restored.Code(); code.Generator != LatestGenerator {
+		t.Errorf("restored generator = %d, want %d", code.Generator, LatestGenerator)
+	}
+
+	// boards saved before the generator was kept were all made by the first one
+	old := `{"version":1,"size":2,"seed":5,"state":0,"blackHoles":[[0,0]]}`
+	if err := json.Unmarshal([]byte(old), &restored); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if code, _ := restored.Code(); code.Generator != GeneratorV1 {
+		t.Errorf("old board generator = %d, want %d", code.Generator, GeneratorV1)
+	}
+}
@@ -0,0 +1,29 @@
+package model
+
+// Hint returns a closed cell that can be proven safe from the opened numbers, ok is false if there is none.
+// Hinted games don't count for high scores.
+func (b *Board) Hint() (x, y int, ok bool) {
+	if b.state != InProgress {
+		return 0, 0, false
+	}
+	s := newSolver(b)
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			if b.cells[x][y].opened {
+				s.opened[x][y] = true
+				s.closedSafe--
+			}
+		}
+	}
+	for s.deduce() {
+		for x := 0; x < b.size; x++ {
+			for y := 0; y < b.size; y++ {
+				if s.opened[x][y] && !b.cells[x][y].opened {
+					b.hinted = true
+					return x, y, true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
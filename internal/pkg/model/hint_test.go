@@ -0,0 +1,37 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestBoard_Hint(t *testing.T) {
+	tests := []struct {
+		name   string
+		opened [][]int
+		wantX  int
+		wantY  int
+		wantOk bool
+	}{
+		{name: "closed board"},
+		{name: "proven safe cell", opened: [][]int{{2, 2}}, wantX: 0, wantY: 0, wantOk: true},
+		{name: "lost", opened: [][]int{{1, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := NewBoard(fixedCoordinatesProvider{points: [][]int{{1, 0}, {0, 2}}}, 3, 2)
+			if err != nil {
+				t.Fatalf("NewBoard() error = %v", err)
+			}
+			for _, p := range tt.opened {
+				board.Open(p[0], p[1])
+			}
+			x, y, ok := board.Hint()
+			if ok != tt.wantOk || x != tt.wantX || y != tt.wantY {
+				t.Errorf("Hint() = %d,%d,%v, want %d,%d,%v", x, y, ok, tt.wantX, tt.wantY, tt.wantOk)
+			}
+			if board.IsPractice() != tt.wantOk {
+				t.Errorf("IsPractice() = %v, want %v", board.IsPractice(), tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package model
+
+// Metrics describes how hard a layout is regardless of how it's played.
+type Metrics struct {
+	// ThreeBV is the minimum number of clicks needed to open all non black hole cells.
+	ThreeBV int
+	// Openings is the number of connected regions of cells without black hole neighbours.
+	Openings int
+	// IsolatedNumbers is the number of numbered cells not bordering any opening.
+	IsolatedNumbers int
+	// Guesses is the number of times the solver gets stuck and has to open a cell without proof,
+	// the very first click isn't counted.
+	Guesses int
+}
+
+func (b *Board) Metrics() Metrics {
+	m := Metrics{}
+	covered := newGrid(b.size)
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			c := b.cells[x][y]
+			if c.blackHole || c.neighboursCount != 0 || covered[x][y] {
+				continue
+			}
+			m.Openings++
+			b.coverOpening(covered, x, y)
+		}
+	}
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			if !b.cells[x][y].blackHole && !covered[x][y] {
+				m.IsolatedNumbers++
+			}
+		}
+	}
+	m.ThreeBV = m.Openings + m.IsolatedNumbers
+	m.Guesses = newSolver(b).guesses()
+	return m
+}
+
+// ThreeBVPerSecond is the efficiency of a won game: 3BV divided by seconds spent.
+func ThreeBVPerSecond(threeBV int, seconds float64) float64 {
+	if seconds <= 0 {
+		return 0
+	}
+	return float64(threeBV) / seconds
+}
+
+// coverOpening marks the opening containing x, y together with its numbered border, the same way openCell does.
+func (b *Board) coverOpening(covered [][]bool, x, y int) {
+	if outsideOfBoard(x, y, b.size) || covered[x][y] {
+		return
+	}
+	covered[x][y] = true
+	if b.cells[x][y].neighboursCount != 0 {
+		return
+	}
+	forEachNeighbour(b.size, x, y, func(nx, ny int) {
+		b.coverOpening(covered, nx, ny)
+	})
+}
+
+func forEachNeighbour(size, x, y int, f func(nx, ny int)) {
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			if (dx != 0 || dy != 0) && !outsideOfBoard(x+dx, y+dy, size) {
+				f(x+dx, y+dy)
+			}
+		}
+	}
+}
+
+func newGrid(size int) [][]bool {
+	g := make([][]bool, size)
+	for i := range g {
+		g[i] = make([]bool, size)
+	}
+	return g
+}
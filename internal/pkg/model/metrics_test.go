@@ -0,0 +1,131 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoard_Metrics(t *testing.T) {
+	type args struct {
+		size           int
+		count          int
+		blackHoleCells [][]int
+	}
+	tests := []struct {
+		name string
+		args args
+		want Metrics
+	}{
+		{
+			name: "single opening with a 50/50",
+			args: args{
+				size:           3,
+				count:          2,
+				blackHoleCells: [][]int{{1, 0}, {0, 2}},
+			},
+			want: Metrics{ThreeBV: 4, Openings: 1, IsolatedNumbers: 3, Guesses: 1},
+		},
+		{
+			name: "opening leaves the left column ambiguous",
+			args: args{
+				size:           5,
+				count:          3,
+				blackHoleCells: [][]int{{1, 1}, {4, 0}, {1, 4}},
+			},
+			want: Metrics{ThreeBV: 11, Openings: 1, IsolatedNumbers: 10, Guesses: 2},
+		},
+		{
+			name: "no openings",
+			args: args{
+				size:           4,
+				count:          5,
+				blackHoleCells: [][]int{{1, 3}, {3, 2}, {0, 0}, {0, 1}, {2, 1}},
+			},
+			want: Metrics{ThreeBV: 11, Openings: 0, IsolatedNumbers: 11, Guesses: 4},
+		},
+		{
+			name: "solvable from the opening",
+			args: args{
+				size:           5,
+				count:          1,
+				blackHoleCells: [][]int{{2, 0}},
+			},
+			want: Metrics{ThreeBV: 1, Openings: 1, IsolatedNumbers: 0, Guesses: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := NewBoard(fixedCoordinatesProvider{points: tt.args.blackHoleCells}, tt.args.size, tt.args.count)
+			if err != nil {
+				t.Fatalf("NewBoard() error = %v", err)
+			}
+			if got := board.Metrics(); got != tt.want {
+				t.Errorf("Metrics() = %+v, want %+v\n%s", got, tt.want, boardToString(board, false))
+			}
+		})
+	}
+}
+
+func TestBoard_Timer(t *testing.T) {
+	start := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	clock := start
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	board, err := NewBoard(fixedCoordinatesProvider{points: [][]int{{1, 0}, {0, 2}}}, 3, 2)
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+	if board.GetElapsed() != 0 {
+		t.Errorf("GetElapsed() before the first move = %v, want 0", board.GetElapsed())
+	}
+
+	board.Open(2, 2)
+	clock = start.Add(3 * time.Second)
+	if board.GetElapsed() != 3*time.Second {
+		t.Errorf("GetElapsed() in progress = %v, want 3s", board.GetElapsed())
+	}
+
+	board.Open(2, 2)
+	board.Open(0, 0)
+	board.Open(2, 0)
+	clock = start.Add(5 * time.Second)
+	board.Open(0, 1)
+	clock = start.Add(60 * time.Second)
+	if board.GetState() != Won || board.GetElapsed() != 5*time.Second {
+		t.Errorf("GetState() = %v, GetElapsed() = %v, want Won in 5s", board.GetState(), board.GetElapsed())
+	}
+	if board.GetMoves() != 4 {
+		t.Errorf("GetMoves() = %d, want 4", board.GetMoves())
+	}
+}
+
+func TestBoard_Pause(t *testing.T) {
+	start := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	clock := start
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	board, err := NewBoard(fixedCoordinatesProvider{points: [][]int{{1, 0}, {0, 2}}}, 3, 2)
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+	board.Pause()
+	if board.IsPaused() {
+		t.Errorf("IsPaused() before the first move = true")
+	}
+
+	board.Open(2, 2)
+	clock = start.Add(3 * time.Second)
+	board.Pause()
+	clock = start.Add(time.Minute)
+	board.Open(0, 0)
+	if board.GetElapsed() != 3*time.Second || board.IsOpened(0, 0) {
+		t.Errorf("GetElapsed() paused = %v, opened %v, want 3s and nothing opened", board.GetElapsed(), board.IsOpened(0, 0))
+	}
+	board.Resume()
+	clock = start.Add(time.Minute + 2*time.Second)
+	if board.GetElapsed() != 5*time.Second {
+		t.Errorf("GetElapsed() resumed = %v, want 5s", board.GetElapsed())
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// BoardOptions pick a board like the flags of the game: the code, the daily board, or the difficulty
+// with the custom size and black holes count. A zero seed picks one from the time, a zero generator the latest one.
+type BoardOptions struct {
+	Difficulty      string
+	Size            int
+	BlackHolesCount int
+	Seed            int64
+	Generator       Generator
+	Code            string
+}
+
+// NewBoard makes the board of the options, now picks the daily board and the missing seed.
+func (o BoardOptions) NewBoard(now time.Time) (Board, error) {
+	if o.Code != "" {
+		c, err := ParseCode(o.Code)
+		if err != nil {
+			return Board{}, err
+		}
+		return c.NewBoard()
+	}
+	if o.Difficulty == Daily.Name {
+		return DailyCode(now).NewBoard()
+	}
+	d := Easy
+	if o.Difficulty != "" {
+		var err error
+		if d, err = DifficultyByName(o.Difficulty); err != nil {
+			return Board{}, err
+		}
+	}
+	d = d.Custom(o.Size, o.BlackHolesCount)
+	seed := o.Seed
+	if seed == 0 {
+		seed = now.UnixNano()
+	}
+	return NewBoard(SeededCoordinatesProvider{Seed: seed, Generator: o.Generator}, d.Size, d.BlackHolesCount)
+}
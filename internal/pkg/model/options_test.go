@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoardOptions_NewBoard(t *testing.T) {
+	now := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	daily := DailyCode(now)
+	tests := []struct {
+		name      string
+		options   BoardOptions
+		wantSize  int
+		wantHoles int
+		wantCode  Code
+		wantErr   bool
+	}{
+		{name: "default", options: BoardOptions{}, wantSize: Easy.Size, wantHoles: Easy.BlackHolesCount},
+		{name: "difficulty", options: BoardOptions{Difficulty: "hard"}, wantSize: Hard.Size, wantHoles: Hard.BlackHolesCount},
+		{
+			name: "custom", options: BoardOptions{Difficulty: "medium", BlackHolesCount: 50, Seed: 1 << 60, Generator: GeneratorV1},
+			wantSize: Medium.Size, wantHoles: 50,
+			wantCode: Code{Generator: GeneratorV1, Topology: Square, Size: Medium.Size, BlackHolesCount: 50, Seed: 1 << 60},
+		},
+		{name: "daily", options: BoardOptions{Difficulty: "daily"}, wantSize: Daily.Size, wantHoles: Daily.BlackHolesCount, wantCode: daily},
+		{name: "code", options: BoardOptions{Code: daily.String(), Difficulty: "hard"}, wantSize: Daily.Size, wantHoles: Daily.BlackHolesCount, wantCode: daily},
+		{name: "unknown difficulty", options: BoardOptions{Difficulty: "insane"}, wantErr: true},
+		{name: "bad code", options: BoardOptions{Code: "nope"}, wantErr: true},
+		{name: "unsupported generator", options: BoardOptions{Generator: 99}, wantErr: true},
+		{name: "too many black holes", options: BoardOptions{Size: 3, BlackHolesCount: 10}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.options.NewBoard(now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewBoard() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.GetSize() != tt.wantSize || got.GetBlackHolesCount() != tt.wantHoles {
+				t.Errorf("NewBoard() size = %d, black holes = %d, want %d, %d", got.GetSize(), got.GetBlackHolesCount(), tt.wantSize, tt.wantHoles)
+			}
+			code, ok := got.Code()
+			if !ok || (tt.wantCode != Code{} && code != tt.wantCode) {
+				t.Errorf("Code() = %+v, %v, want %+v", code, ok, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// boardFormatVersion is bumped on incompatible changes of the saved board format.
+const boardFormatVersion = 1
+
+type savedBoard struct {
+	Version    int           `json:"version"`
+	Size       int           `json:"size"`
+	Seed       int64         `json:"seed"`
+	Generator  Generator     `json:"generator,omitempty"`
+	State      State         `json:"state"`
+	Moves      int           `json:"moves"`
+	Elapsed    time.Duration `json:"elapsed"`
+	Started    bool          `json:"started"`
+	BlackHoles [][2]int      `json:"blackHoles"`
+	Opened     [][2]int      `json:"opened"`
+	Flagged    [][2]int      `json:"flagged"`
+	LostAt     *[2]int       `json:"lostAt,omitempty"`
+	Practice   bool          `json:"practice,omitempty"`
+	Undone     bool          `json:"undone,omitempty"`
+	Hinted     bool          `json:"hinted,omitempty"`
+	Reloaded   bool          `json:"reloaded,omitempty"`
+}
+
+// MarshalJSON saves the whole game including hidden black holes and the timer.
+func (b Board) MarshalJSON() ([]byte, error) {
+	sb := savedBoard{
+		Version:   boardFormatVersion,
+		Size:      b.size,
+		Seed:      b.seed,
+		Generator: b.generator,
+		State:     b.state,
+		Moves:     b.moves,
+		Elapsed:   b.GetElapsed(),
+		Started:   !b.startedAt.IsZero(),
+		Practice:  b.practice,
+		Undone:    b.undone,
+		Hinted:    b.hinted,
+		Reloaded:  b.reloaded,
+	}
+	for y := 0; y < b.size; y++ {
+		for x := 0; x < b.size; x++ {
+			c := b.cells[x][y]
+			p := [2]int{x, y}
+			if c.blackHole {
+				sb.BlackHoles = append(sb.BlackHoles, p)
+			}
+			if c.opened {
+				sb.Opened = append(sb.Opened, p)
+			}
+			if c.flagged {
+				sb.Flagged = append(sb.Flagged, p)
+			}
+		}
+	}
+	if b.state == Lost {
+		sb.LostAt = &[2]int{b.lostAt.x, b.lostAt.y}
+	}
+	return json.Marshal(sb)
+}
+
+// UnmarshalJSON restores a board saved by MarshalJSON, the timer continues from the saved time.
+func (b *Board) UnmarshalJSON(data []byte) error {
+	var sb savedBoard
+	if err := json.Unmarshal(data, &sb); err != nil {
+		return err
+	}
+	if sb.Version <= 0 || sb.Version > boardFormatVersion {
+		return fmt.Errorf("unsupported board format version %d", sb.Version)
+	}
+
+	blackHoles, err := toPoints(sb.BlackHoles, sb.Size)
+	if err != nil {
+		return err
+	}
+	board, err := NewBoardFromLayout(sb.Size, blackHoles)
+	if err != nil {
+		return err
+	}
+	board.seed = sb.Seed
+	board.generator = sb.Generator
+	if board.generator == 0 && board.seed != 0 {
+		// saved before the generator was kept, only the first one existed
+		board.generator = GeneratorV1
+	}
+	board.moves = sb.Moves
+	board.practice = sb.Practice
+	board.undone = sb.Undone
+	board.hinted = sb.Hinted
+	board.reloaded = sb.Reloaded
+
+	opened, err := toPoints(sb.Opened, sb.Size)
+	if err != nil {
+		return err
+	}
+	var exploded []Point
+	for _, p := range opened {
+		c := &board.cells[p.x][p.y]
+		if c.opened {
+			continue
+		}
+		c.opened = true
+		if c.blackHole {
+			exploded = append(exploded, p)
+		} else {
+			board.closedNonBlackHoleCellsCount--
+		}
+	}
+	flagged, err := toPoints(sb.Flagged, sb.Size)
+	if err != nil {
+		return err
+	}
+	for _, p := range flagged {
+		if !board.cells[p.x][p.y].flagged && !board.cells[p.x][p.y].opened {
+			board.cells[p.x][p.y].flagged = true
+			board.flagsCount++
+		}
+	}
+
+	// the renderers trust the state, a broken save mustn't point them off the board
+	switch sb.State {
+	case InProgress, Won:
+		if len(exploded) > 0 {
+			return fmt.Errorf("%s board with the opened black hole %d,%d", sb.State, exploded[0].x, exploded[0].y)
+		}
+		if sb.State == Won && board.closedNonBlackHoleCellsCount > 0 {
+			return fmt.Errorf("won board with %d closed safe cells", board.closedNonBlackHoleCellsCount)
+		}
+	case Lost:
+		if sb.LostAt == nil {
+			return fmt.Errorf("lost board without the black hole position")
+		}
+		lostAt, err := toPoints([][2]int{*sb.LostAt}, sb.Size)
+		if err != nil {
+			return err
+		}
+		board.lostAt = lostAt[0]
+		if len(exploded) != 1 || exploded[0] != board.lostAt {
+			return fmt.Errorf("lost board at %d,%d, which isn't its one opened black hole", board.lostAt.x, board.lostAt.y)
+		}
+	default:
+		return fmt.Errorf("unknown board state %d", sb.State)
+	}
+	board.state = sb.State
+	if sb.Started {
+		board.startedAt = now().Add(-sb.Elapsed)
+		board.finishedAt = now()
+	}
+
+	*b = board
+	return nil
+}
+
+func toPoints(coordinates [][2]int, size int) ([]Point, error) {
+	points := make([]Point, 0, len(coordinates))
+	for _, c := range coordinates {
+		if outsideOfBoard(c[0], c[1], size) {
+			return nil, fmt.Errorf("cell %d,%d is outside of the %dx%d board", c[0], c[1], size, size)
+		}
+		points = append(points, Point{x: c[0], y: c[1]})
+	}
+	return points, nil
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBoard_JSONRoundTrip(t *testing.T) {
+	start := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	clock := start
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		name        string
+		openedCells [][]int
+		flagged     [][]int
+		reloaded    bool
+		wantState   State
+		wantElapsed time.Duration
+	}{
+		{
+			name:        "in progress with flags",
+			openedCells: [][]int{{2, 0}, {2, 3}},
+			flagged:     [][]int{{0, 0}, {3, 2}},
+			wantState:   InProgress,
+			wantElapsed: 7 * time.Second,
+		},
+		{
+			name:        "lost",
+			openedCells: [][]int{{2, 0}, {0, 1}},
+			wantState:   Lost,
+		},
+		{
+			name:        "not started",
+			openedCells: [][]int{},
+			wantState:   InProgress,
+		},
+		{
+			name:        "reloaded",
+			openedCells: [][]int{{2, 0}},
+			reloaded:    true,
+			wantState:   InProgress,
+			wantElapsed: 7 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock = start
+			board, err := NewBoard(fixedCoordinatesProvider{points: [][]int{{1, 3}, {3, 2}, {0, 0}, {0, 1}}}, 4, 4)
+			if err != nil {
+				t.Fatalf("NewBoard() error = %v", err)
+			}
+			board.seed = SEED
+			for _, p := range tt.openedCells {
+				board.Open(p[0], p[1])
+			}
+			for _, p := range tt.flagged {
+				board.ToggleFlag(p[0], p[1])
+			}
+			if tt.reloaded {
+				board.SetReloaded()
+			}
+			clock = start.Add(7 * time.Second)
+
+			data, err := json.Marshal(board)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			clock = start.Add(time.Hour)
+			var restored Board
+			if err := json.Unmarshal(data, &restored); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			if got, want := boardToString(restored, true), boardToString(board, true); got != want {
+				t.Errorf("opened cells:\n%s\nwant:\n%s", got, want)
+			}
+			if got, want := boardToString(restored, false), boardToString(board, false); got != want {
+				t.Errorf("layout:\n%s\nwant:\n%s", got, want)
+			}
+			if restored.GetState() != tt.wantState || restored.GetMoves() != board.GetMoves() ||
+				restored.GetFlagsCount() != len(tt.flagged) || restored.GetSeed() != SEED ||
+				restored.GetOpenedCount() != board.GetOpenedCount() {
+				t.Errorf("restored %+v, want state %v, moves %d, flags %d", restored, tt.wantState, board.GetMoves(), len(tt.flagged))
+			}
+			if restored.IsPractice() != tt.reloaded {
+				t.Errorf("IsPractice() = %v, want %v", restored.IsPractice(), tt.reloaded)
+			}
+			if restored.GetElapsed() != tt.wantElapsed {
+				t.Errorf("GetElapsed() = %v, want %v", restored.GetElapsed(), tt.wantElapsed)
+			}
+			for _, p := range tt.flagged {
+				if !restored.IsFlagged(p[0], p[1]) {
+					t.Errorf("IsFlagged(%d, %d) = false, want true", p[0], p[1])
+				}
+			}
+		})
+	}
+}
+
+func TestBoard_UnmarshalJSONErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		errorMessage string
+	}{
+		{
+			name:         "future version",
+			data:         `{"version":99,"size":3,"blackHoles":[[0,0]]}`,
+			errorMessage: "unsupported board format version 99",
+		},
+		{
+			name:         "black hole outside",
+			data:         `{"version":1,"size":3,"blackHoles":[[3,0]]}`,
+			errorMessage: "cell 3,0 is outside of the 3x3 board",
+		},
+		{
+			name:         "black hole twice",
+			data:         `{"version":1,"size":3,"blackHoles":[[1,0],[0,2],[1,0]]}`,
+			errorMessage: "black hole 1,0 is listed twice",
+		},
+		{
+			name:         "lost without position",
+			data:         `{"version":1,"size":3,"state":2,"blackHoles":[[1,0]]}`,
+			errorMessage: "lost board without the black hole position",
+		},
+		{
+			name:         "lost outside",
+			data:         `{"version":1,"size":3,"state":2,"blackHoles":[[1,0]],"opened":[[1,0]],"lostAt":[7,0]}`,
+			errorMessage: "cell 7,0 is outside of the 3x3 board",
+		},
+		{
+			name:         "lost on a safe cell",
+			data:         `{"version":1,"size":3,"state":2,"blackHoles":[[1,0]],"opened":[[0,0]],"lostAt":[0,0]}`,
+			errorMessage: "lost board at 0,0, which isn't its one opened black hole",
+		},
+		{
+			name:         "lost on a closed black hole",
+			data:         `{"version":1,"size":3,"state":2,"blackHoles":[[1,0]],"lostAt":[1,0]}`,
+			errorMessage: "lost board at 1,0, which isn't its one opened black hole",
+		},
+		{
+			name:         "lost with two opened black holes",
+			data:         `{"version":1,"size":3,"state":2,"blackHoles":[[1,0],[0,2]],"opened":[[1,0],[0,2]],"lostAt":[1,0]}`,
+			errorMessage: "lost board at 1,0, which isn't its one opened black hole",
+		},
+		{
+			name:         "in progress with an opened black hole",
+			data:         `{"version":1,"size":3,"state":0,"blackHoles":[[1,0]],"opened":[[1,0]]}`,
+			errorMessage: "in_progress board with the opened black hole 1,0",
+		},
+		{
+			name:         "won with closed safe cells",
+			data:         `{"version":1,"size":3,"state":1,"blackHoles":[[1,0]],"opened":[[0,0]]}`,
+			errorMessage: "won board with 7 closed safe cells",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Board
+			err := json.Unmarshal([]byte(tt.data), &b)
+			if err == nil || err.Error() != tt.errorMessage {
+				t.Errorf("Unmarshal() error = %v, want %v", err, tt.errorMessage)
+			}
+		})
+	}
+}
@@ -5,10 +5,20 @@ import (
 	"math/rand"
 )
 
+// RandomCoordinatesProvider is the first generator, math/rand doesn't promise the same numbers in every
+// Go version, so new boards use SeededCoordinatesProvider.
 type RandomCoordinatesProvider struct {
 	Seed int64
 }
 
+func (r RandomCoordinatesProvider) getSeed() int64 {
+	return r.Seed
+}
+
+func (r RandomCoordinatesProvider) getGenerator() Generator {
+	return GeneratorV1
+}
+
 func (r RandomCoordinatesProvider) coordinates(size, count int) ([]Point, error) {
 	if size <= 0 {
 		return nil, fmt.Errorf("size should be greater then 0")
@@ -0,0 +1,274 @@
+package model
+
+// maxSolverNodes limits the configurations enumeration on a single frontier component,
+// huge components are left to guessing rather than stalling the game.
+const maxSolverNodes = 200000
+
+// solver plays a layout using only what a player could see. It knows the layout,
+// so when it's stuck it "guesses" a safe cell to keep going.
+type solver struct {
+	board      *Board
+	opened     [][]bool
+	blackHoles [][]bool
+	closedSafe int
+}
+
+func newSolver(b *Board) *solver {
+	closedSafe := 0
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			if !b.cells[x][y].blackHole {
+				closedSafe++
+			}
+		}
+	}
+	return &solver{
+		board:      b,
+		opened:     newGrid(b.size),
+		blackHoles: newGrid(b.size),
+		closedSafe: closedSafe,
+	}
+}
+
+func (s *solver) guesses() int {
+	guesses := 0
+	first := true
+	for s.closedSafe > 0 {
+		if s.deduce() {
+			continue
+		}
+		if !first {
+			guesses++
+		}
+		first = false
+		s.open(s.pickSafe())
+	}
+	return guesses
+}
+
+func (s *solver) open(x, y int) {
+	if outsideOfBoard(x, y, s.board.size) || s.opened[x][y] {
+		return
+	}
+	s.opened[x][y] = true
+	s.closedSafe--
+	if s.board.cells[x][y].neighboursCount == 0 {
+		forEachNeighbour(s.board.size, x, y, s.open)
+	}
+}
+
+// pickSafe prefers the openings as a human would start with, then cells next to the opened area.
+func (s *solver) pickSafe() (int, int) {
+	fx, fy, found := -1, -1, false
+	for x := 0; x < s.board.size; x++ {
+		for y := 0; y < s.board.size; y++ {
+			c := s.board.cells[x][y]
+			if s.opened[x][y] || c.blackHole {
+				continue
+			}
+			if c.neighboursCount == 0 {
+				return x, y
+			}
+			if !found || (!s.isFrontier(fx, fy) && s.isFrontier(x, y)) {
+				fx, fy, found = x, y, true
+			}
+		}
+	}
+	return fx, fy
+}
+
+func (s *solver) isFrontier(x, y int) bool {
+	frontier := false
+	forEachNeighbour(s.board.size, x, y, func(nx, ny int) {
+		frontier = frontier || s.opened[nx][ny]
+	})
+	return frontier
+}
+
+type constraint struct {
+	cells    []int
+	required int
+}
+
+// deduce opens or marks at least one cell if it can be proven from the opened numbers.
+func (s *solver) deduce() bool {
+	unknown, constraints := s.frontier()
+	if len(constraints) == 0 {
+		return false
+	}
+	progress := false
+	for _, c := range constraints {
+		if c.required == 0 || c.required == len(c.cells) {
+			for _, i := range c.cells {
+				progress = s.resolve(unknown[i], c.required != 0) || progress
+			}
+		}
+	}
+	if progress {
+		return true
+	}
+
+	for _, component := range components(len(unknown), constraints) {
+		holes, solutions := enumerate(component.cells, component.constraints)
+		if solutions == 0 {
+			continue
+		}
+		for _, i := range component.cells {
+			if holes[i] == 0 || holes[i] == solutions {
+				progress = s.resolve(unknown[i], holes[i] != 0) || progress
+			}
+		}
+	}
+	return progress
+}
+
+func (s *solver) resolve(p Point, blackHole bool) bool {
+	if blackHole {
+		if s.blackHoles[p.x][p.y] {
+			return false
+		}
+		s.blackHoles[p.x][p.y] = true
+		return true
+	}
+	s.open(p.x, p.y)
+	return true
+}
+
+// frontier returns closed cells next to opened numbers and the constraints the numbers put on them.
+func (s *solver) frontier() ([]Point, []constraint) {
+	var unknown []Point
+	index := map[Point]int{}
+	var constraints []constraint
+	for x := 0; x < s.board.size; x++ {
+		for y := 0; y < s.board.size; y++ {
+			if !s.opened[x][y] || s.board.cells[x][y].neighboursCount == 0 {
+				continue
+			}
+			c := constraint{required: s.board.cells[x][y].neighboursCount}
+			forEachNeighbour(s.board.size, x, y, func(nx, ny int) {
+				switch {
+				case s.opened[nx][ny]:
+				case s.blackHoles[nx][ny]:
+					c.required--
+				default:
+					p := Point{x: nx, y: ny}
+					i, ok := index[p]
+					if !ok {
+						i = len(unknown)
+						index[p] = i
+						unknown = append(unknown, p)
+					}
+					c.cells = append(c.cells, i)
+				}
+			})
+			if len(c.cells) > 0 {
+				constraints = append(constraints, c)
+			}
+		}
+	}
+	return unknown, constraints
+}
+
+type component struct {
+	cells       []int
+	constraints []constraint
+}
+
+// components splits the frontier into groups of cells that don't share any constraint.
+func components(n int, constraints []constraint) []component {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, c := range constraints {
+		for _, i := range c.cells[1:] {
+			parent[find(i)] = find(c.cells[0])
+		}
+	}
+
+	byRoot := map[int]*component{}
+	var roots []int
+	for i := 0; i < n; i++ {
+		r := find(i)
+		if byRoot[r] == nil {
+			byRoot[r] = &component{}
+			roots = append(roots, r)
+		}
+		byRoot[r].cells = append(byRoot[r].cells, i)
+	}
+	for _, c := range constraints {
+		comp := byRoot[find(c.cells[0])]
+		comp.constraints = append(comp.constraints, c)
+	}
+	res := make([]component, 0, len(roots))
+	for _, r := range roots {
+		res = append(res, *byRoot[r])
+	}
+	return res
+}
+
+// enumerate counts, for every cell, in how many of the consistent configurations it's a black hole.
+// Returns zero solutions if there are too many configurations to check.
+func enumerate(cells []int, constraints []constraint) (holes map[int]int, solutions int) {
+	holes = map[int]int{}
+	assignment := map[int]int{}
+	nodes := 0
+	byCell := map[int][]constraint{}
+	for _, c := range constraints {
+		for _, i := range c.cells {
+			byCell[i] = append(byCell[i], c)
+		}
+	}
+
+	// only the constraints of the just assigned cell can become violated
+	consistent := func(cell int) bool {
+		for _, c := range byCell[cell] {
+			sum, free := 0, 0
+			for _, i := range c.cells {
+				v, ok := assignment[i]
+				if !ok {
+					free++
+				}
+				sum += v
+			}
+			if sum > c.required || sum+free < c.required {
+				return false
+			}
+		}
+		return true
+	}
+
+	var walk func(k int) bool
+	walk = func(k int) bool {
+		nodes++
+		if nodes > maxSolverNodes {
+			return false
+		}
+		if k == len(cells) {
+			solutions++
+			for i, v := range assignment {
+				holes[i] += v
+			}
+			return true
+		}
+		for v := 0; v <= 1; v++ {
+			assignment[cells[k]] = v
+			if consistent(cells[k]) && !walk(k+1) {
+				return false
+			}
+		}
+		delete(assignment, cells[k])
+		return true
+	}
+	if !walk(0) {
+		return nil, 0
+	}
+	return holes, solutions
+}
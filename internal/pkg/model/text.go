@@ -0,0 +1,277 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Text format symbols. A layout shows every cell: black holes and the numbers of the other cells.
+// A snapshot shows what the player sees: closed cells, flags and the opened cells.
+const (
+	BlackHoleSymbol = '*'
+	ClosedSymbol    = '?'
+	FlagSymbol      = 'F'
+	// SafeSymbol marks a cell without a black hole in a layout when the number isn't worth writing.
+	SafeSymbol = '.'
+)
+
+// SnapshotCell is the number of an opened cell or one of Closed, Flag and Exploded.
+type SnapshotCell int
+
+const (
+	Closed   SnapshotCell = -1
+	Flag     SnapshotCell = -2
+	Exploded SnapshotCell = -3
+)
+
+// Snapshot is the board as the player sees it.
+type Snapshot struct {
+	cells [][]SnapshotCell
+}
+
+func (s Snapshot) GetSize() int {
+	return len(s.cells)
+}
+
+func (s Snapshot) Get(x, y int) SnapshotCell {
+	return s.cells[x][y]
+}
+
+func (s Snapshot) String() string {
+	return formatGrid(len(s.cells), func(x, y int) rune {
+		return s.cells[x][y].Symbol()
+	})
+}
+
+// Symbol returns the text format symbol of the cell, the bot, the HTTP API and the page read the same symbols.
+func (c SnapshotCell) Symbol() rune {
+	switch c {
+	case Closed:
+		return ClosedSymbol
+	case Flag:
+		return FlagSymbol
+	case Exploded:
+		return BlackHoleSymbol
+	}
+	return rune('0' + c)
+}
+
+// Snapshot returns what the player sees on the board.
+func (b *Board) Snapshot() Snapshot {
+	s := Snapshot{cells: make([][]SnapshotCell, b.size)}
+	for x := range s.cells {
+		s.cells[x] = make([]SnapshotCell, b.size)
+		for y := range s.cells[x] {
+			switch c := b.cells[x][y]; {
+			case c.opened && c.blackHole:
+				s.cells[x][y] = Exploded
+			case c.opened:
+				s.cells[x][y] = SnapshotCell(c.neighboursCount)
+			case c.flagged:
+				s.cells[x][y] = Flag
+			default:
+				s.cells[x][y] = Closed
+			}
+		}
+	}
+	return s
+}
+
+// Layout returns the black holes and the numbers of all the cells, opened or not.
+func (b *Board) Layout() string {
+	return formatGrid(b.size, func(x, y int) rune {
+		if b.cells[x][y].blackHole {
+			return BlackHoleSymbol
+		}
+		return rune('0' + b.cells[x][y].neighboursCount)
+	})
+}
+
+// String returns the layout followed by the snapshot, ParseBoard reads it back.
+func (b Board) String() string {
+	return b.Layout() + "\n" + b.Snapshot().String()
+}
+
+// ParseLayout creates a board from a layout, the numbers are checked against the black holes.
+func ParseLayout(text string) (Board, error) {
+	rows, err := parseGrid(text)
+	if err != nil {
+		return Board{}, err
+	}
+	var blackHoles []Point
+	for y, row := range rows {
+		for x, r := range row {
+			switch {
+			case r == BlackHoleSymbol:
+				blackHoles = append(blackHoles, Point{x: x, y: y})
+			case r != SafeSymbol && !isNumber(r):
+				return Board{}, fmt.Errorf("unknown layout symbol %q at %d,%d", r, x, y)
+			}
+		}
+	}
+	board, err := NewBoardFromLayout(len(rows), blackHoles)
+	if err != nil {
+		return Board{}, err
+	}
+	for y, row := range rows {
+		for x, r := range row {
+			if !isNumber(r) {
+				continue
+			}
+			if n := int(r - '0'); n != board.cells[x][y].neighboursCount {
+				return Board{}, fmt.Errorf("cell %d,%d shows %d, want %d", x, y, n, board.cells[x][y].neighboursCount)
+			}
+		}
+	}
+	return board, nil
+}
+
+// ParseSnapshot reads a snapshot printed by Snapshot.String.
+func ParseSnapshot(text string) (Snapshot, error) {
+	rows, err := parseGrid(text)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	s := Snapshot{cells: make([][]SnapshotCell, len(rows))}
+	for x := range s.cells {
+		s.cells[x] = make([]SnapshotCell, len(rows))
+	}
+	for y, row := range rows {
+		for x, r := range row {
+			switch {
+			case r == ClosedSymbol:
+				s.cells[x][y] = Closed
+			case r == FlagSymbol:
+				s.cells[x][y] = Flag
+			case r == BlackHoleSymbol:
+				s.cells[x][y] = Exploded
+			case isNumber(r):
+				s.cells[x][y] = SnapshotCell(r - '0')
+			default:
+				return Snapshot{}, fmt.Errorf("unknown snapshot symbol %q at %d,%d", r, x, y)
+			}
+		}
+	}
+	return s, nil
+}
+
+// Restore opens and flags the cells as they are in the snapshot, the game continues from there.
+// The moves and the timer start from zero and nothing can be undone.
+func (b *Board) Restore(s Snapshot) error {
+	if s.GetSize() != b.size {
+		return fmt.Errorf("snapshot is %dx%d, board is %dx%d", s.GetSize(), s.GetSize(), b.size, b.size)
+	}
+	exploded := 0
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			c := b.cells[x][y]
+			switch v := s.cells[x][y]; {
+			case v == Exploded && !c.blackHole:
+				return fmt.Errorf("cell %d,%d isn't a black hole", x, y)
+			case v == Exploded:
+				exploded++
+			case v >= 0 && c.blackHole:
+				return fmt.Errorf("cell %d,%d is a black hole", x, y)
+			case v >= 0 && int(v) != c.neighboursCount:
+				return fmt.Errorf("cell %d,%d shows %d, want %d", x, y, v, c.neighboursCount)
+			}
+		}
+	}
+	if exploded > 1 {
+		return fmt.Errorf("snapshot has %d opened black holes, want at most 1", exploded)
+	}
+
+	b.state = InProgress
+	b.closedNonBlackHoleCellsCount = b.size*b.size - b.blackHolesCount
+	b.flagsCount = 0
+	b.moves = 0
+	b.lostAt = Point{}
+	b.startedAt, b.finishedAt = time.Time{}, time.Time{}
+	b.history, b.future = nil, nil
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			c := &b.cells[x][y]
+			v := s.cells[x][y]
+			c.opened = v >= 0 || v == Exploded
+			c.flagged = v == Flag
+			switch {
+			case v == Exploded:
+				b.lostAt = Point{x: x, y: y}
+				b.state = Lost
+			case c.opened:
+				b.closedNonBlackHoleCellsCount--
+			case c.flagged:
+				b.flagsCount++
+			}
+		}
+	}
+	if b.state == InProgress && b.closedNonBlackHoleCellsCount == 0 {
+		b.state = Won
+	}
+	return nil
+}
+
+// ParseBoard reads a layout optionally followed by an empty line and a snapshot, as Board.String prints it.
+func ParseBoard(text string) (Board, error) {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n")), "\n")
+	blank := len(lines)
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			blank = i
+			break
+		}
+	}
+	board, err := ParseLayout(strings.Join(lines[:blank], "\n"))
+	if err != nil {
+		return Board{}, err
+	}
+	if blank == len(lines) {
+		return board, nil
+	}
+	s, err := ParseSnapshot(strings.Join(lines[blank+1:], "\n"))
+	if err != nil {
+		return Board{}, err
+	}
+	if err := board.Restore(s); err != nil {
+		return Board{}, err
+	}
+	return board, nil
+}
+
+func isNumber(r rune) bool {
+	return r >= '0' && r <= '8'
+}
+
+func formatGrid(size int, symbol func(x, y int) rune) string {
+	var sb strings.Builder
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if x > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteRune(symbol(x, y))
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// parseGrid splits the text into square rows of symbols, the spaces between the symbols are optional.
+func parseGrid(text string) ([][]rune, error) {
+	var rows [][]rune
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		row := []rune(strings.Join(strings.Fields(line), ""))
+		if len(row) == 0 {
+			return nil, fmt.Errorf("empty row %d", len(rows))
+		}
+		rows = append(rows, row)
+	}
+	for y, row := range rows {
+		if len(row) != len(rows) {
+			return nil, fmt.Errorf("row %d has %d cells, want %d", y, len(row), len(rows))
+		}
+	}
+	return rows, nil
+}
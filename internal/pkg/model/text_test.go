@@ -0,0 +1,187 @@
+package model
+
+import (
+	"os"
+	"testing"
+)
+
+func TestBoard_TextRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		openedCells [][]int
+		flagged     [][]int
+		wantState   State
+		want        string
+	}{
+		{
+			name:      "new",
+			wantState: InProgress,
+			want: "* 2 0 0\n* 2 1 1\n2 2 2 *\n1 * 2 1\n" +
+				"\n" +
+				"? ? ? ?\n? ? ? ?\n? ? ? ?\n? ? ? ?\n",
+		},
+		{
+			name:        "in progress with flags",
+			openedCells: [][]int{{2, 0}, {2, 3}},
+			flagged:     [][]int{{0, 1}, {3, 2}},
+			wantState:   InProgress,
+			want: "* 2 0 0\n* 2 1 1\n2 2 2 *\n1 * 2 1\n" +
+				"\n" +
+				"? 2 0 0\nF 2 1 1\n? ? ? F\n? ? 2 ?\n",
+		},
+		{
+			name:        "lost",
+			openedCells: [][]int{{2, 0}, {0, 1}},
+			wantState:   Lost,
+			want: "* 2 0 0\n* 2 1 1\n2 2 2 *\n1 * 2 1\n" +
+				"\n" +
+				"? 2 0 0\n* 2 1 1\n? ? ? ?\n? ? ? ?\n",
+		},
+		{
+			name:        "won",
+			openedCells: [][]int{{2, 0}, {0, 2}, {1, 2}, {2, 2}, {0, 3}, {2, 3}, {3, 3}},
+			wantState:   Won,
+			want: "* 2 0 0\n* 2 1 1\n2 2 2 *\n1 * 2 1\n" +
+				"\n" +
+				"? 2 0 0\n? 2 1 1\n2 2 2 ?\n1 ? 2 1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := NewBoard(fixedCoordinatesProvider{points: [][]int{{1, 3}, {3, 2}, {0, 0}, {0, 1}}}, 4, 4)
+			if err != nil {
+				t.Fatalf("NewBoard() error = %v", err)
+			}
+			for _, p := range tt.openedCells {
+				board.Open(p[0], p[1])
+			}
+			for _, p := range tt.flagged {
+				board.ToggleFlag(p[0], p[1])
+			}
+
+			if got := board.String(); got != tt.want {
+				t.Fatalf("String():\n%s\nwant:\n%s", got, tt.want)
+			}
+			parsed, err := ParseBoard(tt.want)
+			if err != nil {
+				t.Fatalf("ParseBoard() error = %v", err)
+			}
+			if got := parsed.String(); got != tt.want {
+				t.Errorf("parsed board:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if parsed.GetState() != tt.wantState || parsed.GetOpenedCount() != board.GetOpenedCount() ||
+				parsed.GetFlagsCount() != len(tt.flagged) {
+				t.Errorf("parsed state %v, opened %d, flags %d, want %v, %d, %d", parsed.GetState(),
+					parsed.GetOpenedCount(), parsed.GetFlagsCount(), tt.wantState, board.GetOpenedCount(), len(tt.flagged))
+			}
+		})
+	}
+}
+
+func TestParseBoard_Golden(t *testing.T) {
+	data, err := os.ReadFile("testdata/in_progress.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board, err := ParseBoard(string(data))
+	if err != nil {
+		t.Fatalf("ParseBoard() error = %v", err)
+	}
+	board.Open(0, 2)
+	board.Open(0, 3)
+	board.Open(1, 2)
+	board.Open(2, 2)
+	board.Open(3, 3)
+	want := `
+		* 2 0 0
+		* 2 1 1
+		2 2 2 *
+		1 * 2 1
+
+		? 2 0 0
+		F 2 1 1
+		2 2 2 F
+		1 ? 2 1
+		`
+	if !equalIgnoreSpaces(board.String(), want) || board.GetState() != Won {
+		t.Errorf("board:\n%s\nstate %v, want:\n%s", board.String(), board.GetState(), want)
+	}
+}
+
+func TestParseBoard_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		errorMessage string
+	}{
+		{
+			name:         "not square",
+			text:         "* 1\n1 1\n0 0",
+			errorMessage: "row 0 has 2 cells, want 3",
+		},
+		{
+			name:         "unknown symbol",
+			text:         "* 1\n1 x",
+			errorMessage: "unknown layout symbol 'x' at 1,1",
+		},
+		{
+			name:         "wrong number",
+			text:         "* 2\n1 1",
+			errorMessage: "cell 1,0 shows 2, want 1",
+		},
+		{
+			name:         "numbers can be left out",
+			text:         "* .\n. .\n\n? 1\n? ?",
+			errorMessage: "",
+		},
+		{
+			name:         "black hole shown as a number",
+			text:         "* 1\n1 1\n\n1 ?\n? ?",
+			errorMessage: "cell 0,0 is a black hole",
+		},
+		{
+			name:         "snapshot of another size",
+			text:         "* 1\n1 1\n\n?",
+			errorMessage: "snapshot is 1x1, board is 2x2",
+		},
+		{
+			name:         "unknown snapshot symbol",
+			text:         "* 1\n1 1\n\n? ?\n? 9",
+			errorMessage: "unknown snapshot symbol '9' at 1,1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBoard(tt.text)
+			if tt.errorMessage == "" {
+				if err != nil {
+					t.Errorf("ParseBoard() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.errorMessage {
+				t.Errorf("ParseBoard() error = %v, want %s", err, tt.errorMessage)
+			}
+		})
+	}
+}
+
+func TestSnapshotCell_Symbol(t *testing.T) {
+	tests := []struct {
+		cell SnapshotCell
+		want rune
+	}{
+		{cell: Closed, want: ClosedSymbol},
+		{cell: Flag, want: FlagSymbol},
+		{cell: Exploded, want: BlackHoleSymbol},
+		{cell: 0, want: '0'},
+		{cell: 8, want: '8'},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			if got := tt.cell.Symbol(); got != tt.want {
+				t.Errorf("Symbol() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package model
+
+type action int
+
+const (
+	openAction action = iota
+	flagAction
+	chordAction
+)
+
+// change is enough to take an action back and to do it again.
+type change struct {
+	action action
+	point  Point
+	opened []Point
+	state  State
+	moves  int
+	lostAt Point
+}
+
+// SetPractice allows to undo opens, including the one that hit a black hole.
+func (b *Board) SetPractice(practice bool) {
+	b.practice = practice
+}
+
+// SetReloaded marks a game loaded from a save slot, it can be loaded again after a loss.
+func (b *Board) SetReloaded() {
+	b.reloaded = true
+}
+
+// IsPractice reports games in practice mode, reloaded ones or where anything was undone or hinted,
+// they don't count for high scores.
+func (b *Board) IsPractice() bool {
+	return b.practice || b.undone || b.hinted || b.reloaded
+}
+
+func (b *Board) CanUndo() bool {
+	return len(b.history) > 0 && (b.practice || b.state == InProgress)
+}
+
+func (b *Board) CanRedo() bool {
+	return len(b.future) > 0 && (b.practice || b.state == InProgress)
+}
+
+// Undo takes back the last action. Out of practice mode only flags placed after the last open can be taken back.
+func (b *Board) Undo() {
+	if !b.CanUndo() {
+		return
+	}
+	ch := b.history[len(b.history)-1]
+	b.history = b.history[:len(b.history)-1]
+	b.future = append(b.future, ch)
+	b.undone = true
+
+	if ch.action == flagAction {
+		b.toggleFlagAt(ch.point.x, ch.point.y)
+		b.emit(CellFlagged{X: ch.point.x, Y: ch.point.y, Flagged: b.cells[ch.point.x][ch.point.y].flagged})
+		return
+	}
+	for _, p := range ch.opened {
+		b.cells[p.x][p.y].opened = false
+	}
+	from := b.state
+	b.state = ch.state
+	b.moves = ch.moves
+	b.lostAt = ch.lostAt
+	b.closedNonBlackHoleCellsCount = 0
+	for x := 0; x < b.size; x++ {
+		for y := 0; y < b.size; y++ {
+			if c := b.cells[x][y]; !c.blackHole && !c.opened {
+				b.closedNonBlackHoleCellsCount++
+			}
+		}
+	}
+
+	b.emit(CellsClosed{Cells: ch.opened})
+	if from != b.state {
+		b.emit(StateChanged{From: from, To: b.state})
+	}
+}
+
+// Redo does the last undone action again.
+func (b *Board) Redo() {
+	if !b.CanRedo() {
+		return
+	}
+	ch := b.future[len(b.future)-1]
+	b.future = b.future[:len(b.future)-1]
+	b.perform(ch.action, ch.point.x, ch.point.y)
+}
+
+// do performs a new action, it makes the undone actions impossible to redo.
+func (b *Board) do(a action, x, y int) {
+	if b.IsPaused() {
+		return
+	}
+	future := b.future
+	b.future = nil
+	if !b.perform(a, x, y) {
+		b.future = future
+	}
+}
+
+// perform runs the action and keeps it in the history if it changed the board.
+func (b *Board) perform(a action, x, y int) bool {
+	ch := change{action: a, point: Point{x: x, y: y}, state: b.state, moves: b.moves, lostAt: b.lostAt}
+	changed := false
+	switch a {
+	case flagAction:
+		changed = b.toggleFlagAt(x, y)
+	case openAction, chordAction:
+		b.journal = &ch.opened
+		if a == openAction {
+			b.openAt(x, y)
+		} else {
+			b.chordAt(x, y)
+		}
+		b.journal = nil
+		changed = len(ch.opened) > 0
+	}
+	if !changed {
+		return false
+	}
+	if a != flagAction && !b.practice {
+		// opens are final out of practice mode, so are the flags before them
+		b.history = nil
+	} else {
+		b.history = append(b.history, ch)
+	}
+	b.emitChange(ch)
+	return true
+}
+
+// emitChange tells the listeners what the action did, in the order it happened.
+func (b *Board) emitChange(ch change) {
+	if ch.moves == 0 && b.moves > 0 {
+		b.emit(GameStarted{})
+	}
+	if ch.action == flagAction {
+		b.emit(CellFlagged{X: ch.point.x, Y: ch.point.y, Flagged: b.cells[ch.point.x][ch.point.y].flagged})
+	} else {
+		b.emit(CellsOpened{X: ch.point.x, Y: ch.point.y, Cells: ch.opened})
+	}
+	if b.state != ch.state {
+		b.emit(StateChanged{From: ch.state, To: b.state})
+	}
+}
+
+func (b *Board) log(x, y int) {
+	if b.journal != nil {
+		*b.journal = append(*b.journal, Point{x: x, y: y})
+	}
+}
@@ -0,0 +1,121 @@
+package model
+
+import "testing"
+
+func TestBoard_UndoRedo(t *testing.T) {
+	type step struct {
+		action string
+		x, y   int
+	}
+	tests := []struct {
+		name            string
+		practice        bool
+		steps           []step
+		wantState       State
+		wantMoves       int
+		wantFlags       int
+		wantPractice    bool
+		wantCanRedo     bool
+		wantOpenedBoard string
+	}{
+		{
+			name:      "undo the losing open in practice",
+			practice:  true,
+			steps:     []step{{action: "open", x: 2, y: 2}, {action: "open", x: 1, y: 0}, {action: "undo"}},
+			wantState: InProgress, wantMoves: 1, wantPractice: true, wantCanRedo: true,
+			wantOpenedBoard: `
+				? ? ?
+				? 2 1
+				? 1 0
+				`,
+		},
+		{
+			name:      "redo the losing open",
+			practice:  true,
+			steps:     []step{{action: "open", x: 2, y: 2}, {action: "open", x: 1, y: 0}, {action: "undo"}, {action: "redo"}},
+			wantState: Lost, wantMoves: 2, wantPractice: true,
+			wantOpenedBoard: `
+				? * ?
+				? 2 1
+				? 1 0
+				`,
+		},
+		{
+			name:     "new action drops redo",
+			practice: true,
+			steps: []step{{action: "open", x: 2, y: 2}, {action: "flag", x: 1, y: 0}, {action: "undo"}, {action: "undo"},
+				{action: "open", x: 0, y: 0}},
+			wantState: InProgress, wantMoves: 1, wantPractice: true,
+			wantOpenedBoard: `
+				1 ? ?
+				? ? ?
+				? ? ?
+				`,
+		},
+		{
+			name:      "only flags after the last open out of practice",
+			steps:     []step{{action: "flag", x: 1, y: 0}, {action: "open", x: 2, y: 2}, {action: "flag", x: 0, y: 2}, {action: "undo"}, {action: "undo"}},
+			wantState: InProgress, wantMoves: 1, wantFlags: 1, wantPractice: true, wantCanRedo: true,
+			wantOpenedBoard: `
+				? ? ?
+				? 2 1
+				? 1 0
+				`,
+		},
+		{
+			name:      "no undo of the losing open out of practice",
+			steps:     []step{{action: "open", x: 2, y: 2}, {action: "open", x: 1, y: 0}, {action: "undo"}},
+			wantState: Lost, wantMoves: 2,
+			wantOpenedBoard: `
+				? * ?
+				? 2 1
+				? 1 0
+				`,
+		},
+		{
+			name:     "win after undo",
+			practice: true,
+			steps: []step{{action: "open", x: 2, y: 2}, {action: "open", x: 0, y: 2}, {action: "undo"},
+				{action: "open", x: 0, y: 0}, {action: "open", x: 2, y: 0}, {action: "open", x: 0, y: 1}},
+			wantState: Won, wantMoves: 4, wantPractice: true,
+			wantOpenedBoard: `
+				1 ? 1
+				2 2 1
+				? 1 0
+				`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := NewBoard(fixedCoordinatesProvider{points: [][]int{{1, 0}, {0, 2}}}, 3, 2)
+			if err != nil {
+				t.Fatalf("NewBoard() error = %v", err)
+			}
+			board.SetPractice(tt.practice)
+			for _, s := range tt.steps {
+				switch s.action {
+				case "open":
+					board.Open(s.x, s.y)
+				case "flag":
+					board.ToggleFlag(s.x, s.y)
+				case "undo":
+					board.Undo()
+				case "redo":
+					board.Redo()
+				}
+			}
+
+			actualOpened := boardToString(board, true)
+			if !equalIgnoreSpaces(actualOpened, tt.wantOpenedBoard) {
+				t.Errorf("Got:\n%s\nWant:\n%s", actualOpened, tt.wantOpenedBoard)
+			}
+			if board.GetState() != tt.wantState || board.GetMoves() != tt.wantMoves || board.GetFlagsCount() != tt.wantFlags {
+				t.Errorf("state %v, moves %d, flags %d, want %v, %d, %d",
+					board.GetState(), board.GetMoves(), board.GetFlagsCount(), tt.wantState, tt.wantMoves, tt.wantFlags)
+			}
+			if board.IsPractice() != tt.wantPractice || board.CanRedo() != tt.wantCanRedo {
+				t.Errorf("IsPractice() = %v, CanRedo() = %v, want %v, %v", board.IsPractice(), board.CanRedo(), tt.wantPractice, tt.wantCanRedo)
+			}
+		})
+	}
+}
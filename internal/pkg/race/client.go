@@ -0,0 +1,92 @@
+package race
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"net"
+	"sync"
+	"time"
+)
+
+// Client is the connection of a player to the host.
+type Client struct {
+	Name     string
+	conn     net.Conn
+	messages chan Message
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// Dial joins the lobby of the host, the name may be refused when it's taken.
+func Dial(addr, name string) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, joinTimeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{conn: conn, messages: make(chan Message, sendBuffer), enc: json.NewEncoder(conn)}
+	sc := bufio.NewScanner(conn)
+	conn.SetDeadline(time.Now().Add(joinTimeout))
+	welcome, err := c.join(sc, name)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	c.Name = welcome.Name
+	go c.read(sc)
+	return c, nil
+}
+
+func (c *Client) join(sc *bufio.Scanner, name string) (Message, error) {
+	if err := c.enc.Encode(Message{Type: JoinMessage, Version: Version, Name: name}); err != nil {
+		return Message{}, err
+	}
+	if !sc.Scan() {
+		if sc.Err() != nil {
+			return Message{}, sc.Err()
+		}
+		return Message{}, errors.New("the host closed the connection")
+	}
+	var m Message
+	if err := json.Unmarshal(sc.Bytes(), &m); err != nil {
+		return Message{}, err
+	}
+	switch m.Type {
+	case WelcomeMessage:
+		return m, nil
+	case ErrorMessage:
+		return Message{}, errors.New(m.Error)
+	}
+	return Message{}, errors.New("unexpected message " + m.Type)
+}
+
+func (c *Client) read(sc *bufio.Scanner) {
+	defer close(c.messages)
+	for sc.Scan() {
+		var m Message
+		if err := json.Unmarshal(sc.Bytes(), &m); err != nil {
+			continue
+		}
+		c.messages <- m
+	}
+}
+
+// Messages returns the messages of the host, it's closed once the connection is.
+func (c *Client) Messages() <-chan Message {
+	return c.messages
+}
+
+// Progress tells the host how far the player got.
+func (c *Client) Progress(opened int, state model.State) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return c.enc.Encode(Message{Type: ProgressMessage, Opened: opened, State: state.String()})
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,51 @@
+package race
+
+// Version is sent on join, the host refuses players of another protocol version.
+const Version = 1
+
+// The protocol is one JSON Message per line over TCP. A player sends join first and gets welcome or error,
+// then lobby updates until the host starts: countdown every second, then start with the board code.
+// Players send progress after every move and get the progress of everyone, the ranking comes once
+// all of them won, lost or left. The lobby of the next race follows.
+const (
+	JoinMessage      = "join"
+	WelcomeMessage   = "welcome"
+	ErrorMessage     = "error"
+	LobbyMessage     = "lobby"
+	CountdownMessage = "countdown"
+	StartMessage     = "start"
+	ProgressMessage  = "progress"
+	RankingMessage   = "ranking"
+)
+
+// Player states, in the progress sent by the players and in the ones sent by the host.
+const (
+	Racing = "in_progress"
+	Won    = "won"
+	Lost   = "lost"
+	Left   = "left"
+)
+
+type Message struct {
+	Type    string `json:"type"`
+	Version int    `json:"version,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// Opened is the number of opened cells of the progress of a player
+	Opened  int      `json:"opened,omitempty"`
+	State   string   `json:"state,omitempty"`
+	Seconds int      `json:"seconds,omitempty"`
+	Code    string   `json:"code,omitempty"`
+	Players []Player `json:"players,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Player is a player as seen by everyone, in the order of joining or in the ranking order.
+type Player struct {
+	Name string `json:"name"`
+	// Percent is the share of the safe cells opened
+	Percent int    `json:"percent"`
+	State   string `json:"state,omitempty"`
+	// Rank and TimeMs, the time from the start to winning or losing, are set in the ranking
+	Rank   int   `json:"rank,omitempty"`
+	TimeMs int64 `json:"timeMs,omitempty"`
+}
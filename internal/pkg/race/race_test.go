@@ -0,0 +1,235 @@
+package race
+
+import (
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, countdown int) (*Server, string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	s := NewServer(Config{Difficulty: model.CustomDifficulty(5, 5), Countdown: countdown})
+	s.tick = time.Millisecond
+	go s.Serve(l)
+	return s, l.Addr().String()
+}
+
+func join(t *testing.T, addr, name string) *Client {
+	t.Helper()
+	c, err := Dial(addr, name)
+	if err != nil {
+		t.Fatalf("Dial(%s) error = %v", name, err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// next skips the messages of the client until one of the type.
+func next(t *testing.T, c *Client, typ string) Message {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case m, ok := <-c.Messages():
+			if !ok {
+				t.Fatalf("%s: connection closed waiting for %s", c.Name, typ)
+			}
+			if m.Type == typ {
+				return m
+			}
+		case <-timeout:
+			t.Fatalf("%s: no %s message", c.Name, typ)
+		}
+	}
+}
+
+// lobbyOf waits for the lobby listing the names.
+func lobbyOf(t *testing.T, c *Client, names ...string) {
+	t.Helper()
+	for {
+		var got []string
+		for _, p := range next(t, c, LobbyMessage).Players {
+			got = append(got, p.Name)
+		}
+		if reflect.DeepEqual(got, names) {
+			return
+		}
+	}
+}
+
+func TestServer_race(t *testing.T) {
+	s, addr := newTestServer(t, 3)
+	alice := join(t, addr, "alice")
+	bob := join(t, addr, "bob")
+	carol := join(t, addr, "carol")
+	lobbyOf(t, alice, "alice", "bob", "carol")
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	for i := 3; i > 0; i-- {
+		if m := next(t, bob, CountdownMessage); m.Seconds != i {
+			t.Errorf("countdown = %d, want %d", m.Seconds, i)
+		}
+	}
+	var codes []string
+	for _, c := range []*Client{alice, bob, carol} {
+		codes = append(codes, next(t, c, StartMessage).Code)
+	}
+	if codes[0] != codes[1] || codes[1] != codes[2] {
+		t.Fatalf("codes = %v, want the same board for everyone", codes)
+	}
+	code, err := model.ParseCode(codes[0])
+	if err != nil || code.Size != 5 || code.BlackHolesCount != 5 {
+		t.Fatalf("ParseCode(%s) = %+v, %v, want a 5x5 board with 5 black holes", codes[0], code, err)
+	}
+	if err := s.Start(); err == nil {
+		t.Errorf("Start() during the race error = nil, want an error")
+	}
+	if _, err := Dial(addr, "dave"); err == nil {
+		t.Errorf("Dial() during the race error = nil, want an error")
+	}
+
+	bob.Progress(5, model.InProgress)
+	for {
+		m := next(t, alice, ProgressMessage)
+		if m.Players[1] == (Player{Name: "bob", Percent: 25, State: Racing}) {
+			break
+		}
+	}
+	alice.Progress(20, model.Won)
+	bob.Progress(10, model.Lost)
+	carol.Progress(2, model.InProgress)
+	carol.Close()
+
+	ranking := next(t, bob, RankingMessage).Players
+	for i := range ranking {
+		ranking[i].TimeMs = 0
+	}
+	want := []Player{
+		{Name: "alice", Percent: 100, State: Won, Rank: 1},
+		{Name: "bob", Percent: 50, State: Lost, Rank: 2},
+		{Name: "carol", Percent: 10, State: Left, Rank: 3},
+	}
+	if !reflect.DeepEqual(ranking, want) {
+		t.Errorf("ranking = %+v, want %+v", ranking, want)
+	}
+	lobbyOf(t, alice, "alice", "bob")
+	join(t, addr, "dave")
+	lobbyOf(t, bob, "alice", "bob", "dave")
+}
+
+func TestServer_finishThenDisconnect(t *testing.T) {
+	s, addr := newTestServer(t, 0)
+	alice := join(t, addr, "alice")
+	bob := join(t, addr, "bob")
+	lobbyOf(t, alice, "alice", "bob")
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	next(t, alice, StartMessage)
+	next(t, bob, StartMessage)
+
+	alice.Progress(3, model.Lost)
+	for {
+		if m := next(t, bob, ProgressMessage); m.Players[0].State == Lost {
+			break
+		}
+	}
+	alice.Close()
+	for {
+		if m := next(t, bob, ProgressMessage); m.Players[0].State == Lost {
+			break
+		}
+	}
+	bob.Progress(20, model.Won)
+
+	ranking := next(t, bob, RankingMessage).Players
+	if len(ranking) != 2 || ranking[0].Name != "bob" || ranking[1].Name != "alice" || ranking[1].State != Lost {
+		t.Errorf("ranking = %+v, want bob then alice who lost", ranking)
+	}
+	lobbyOf(t, bob, "bob")
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start() of the next race error = %v", err)
+	}
+	next(t, bob, StartMessage)
+}
+
+func TestServer_ranking(t *testing.T) {
+	tests := []struct {
+		name    string
+		players []*player
+		want    []string
+	}{
+		{
+			name: "winners by time",
+			players: []*player{
+				{name: "slow", state: Won, opened: 20, finished: 9 * time.Second},
+				{name: "fast", state: Won, opened: 20, finished: 3 * time.Second},
+			},
+			want: []string{"fast", "slow"},
+		},
+		{
+			name: "winners first, then by cleared cells",
+			players: []*player{
+				{name: "lost", state: Lost, opened: 19, finished: time.Second},
+				{name: "left", state: Left, opened: 2},
+				{name: "won", state: Won, opened: 20, finished: time.Minute},
+				{name: "far", state: Left, opened: 10},
+			},
+			want: []string{"won", "lost", "far", "left"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{players: tt.players, safeCells: 20}
+			var got []string
+			for i, p := range s.ranking() {
+				if p.Rank != i+1 {
+					t.Errorf("rank of %s = %d, want %d", p.Name, p.Rank, i+1)
+				}
+				got = append(got, p.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ranking = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDial_refused(t *testing.T) {
+	s, addr := newTestServer(t, 0)
+	join(t, addr, "alice")
+
+	tests := []struct {
+		name   string
+		player string
+	}{
+		{name: "taken", player: "alice"},
+		{name: "empty", player: " "},
+		{name: "spaces", player: "al ice"},
+		{name: "too long", player: "galaxy_tramp_ace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c, err := Dial(addr, tt.player); err == nil {
+				c.Close()
+				t.Errorf("Dial(%q) error = nil, want an error", tt.player)
+			}
+		})
+	}
+
+	if err := NewServer(Config{}).Start(); err == nil {
+		t.Errorf("Start() of an empty lobby error = nil, want an error")
+	}
+	if err := s.Start(); err != nil {
+		t.Errorf("Start() error = %v", err)
+	}
+}
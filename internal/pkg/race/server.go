@@ -0,0 +1,305 @@
+package race
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxNameLength keeps the names fitting the banners of the others.
+	MaxNameLength = 12
+	joinTimeout   = 10 * time.Second
+	writeTimeout  = 5 * time.Second
+	sendBuffer    = 64
+)
+
+type Config struct {
+	Difficulty model.Difficulty
+	// Countdown is the number of seconds counted down before the start.
+	Countdown int
+}
+
+// Server hosts the races: it keeps the lobby, starts the races on the same board for everyone and ranks the players.
+type Server struct {
+	difficulty model.Difficulty
+	countdown  int
+	// tick is the countdown step
+	tick time.Duration
+	now  func() time.Time
+
+	mu        sync.Mutex
+	players   []*player
+	counting  bool
+	racing    bool
+	safeCells int
+	startedAt time.Time
+}
+
+type player struct {
+	name string
+	send chan Message
+	// conn is closed by the writer once send is closed
+	conn     net.Conn
+	opened   int
+	state    string
+	finished time.Duration
+	// gone is set once the connection is closed, the player keeps the result until the ranking
+	gone bool
+}
+
+func NewServer(cfg Config) *Server {
+	return &Server{difficulty: cfg.Difficulty, countdown: cfg.Countdown, tick: time.Second, now: time.Now}
+}
+
+// Serve accepts the players until the listener is closed.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// Start counts down and starts a race for the players of the lobby.
+func (s *Server) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.racing:
+		return errors.New("the race is on")
+	case s.counting:
+		return errors.New("the race is starting")
+	case len(s.players) == 0:
+		return errors.New("nobody joined the race")
+	}
+	seed := s.now().UnixMilli()
+	board, err := model.NewBoard(model.SeededCoordinatesProvider{Seed: seed}, s.difficulty.Size, s.difficulty.BlackHolesCount)
+	if err != nil {
+		return err
+	}
+	code, _ := board.Code()
+	s.counting = true
+	go s.start(code)
+	return nil
+}
+
+func (s *Server) start(code model.Code) {
+	for i := s.countdown; i > 0; i-- {
+		s.mu.Lock()
+		s.broadcast(Message{Type: CountdownMessage, Seconds: i})
+		s.mu.Unlock()
+		time.Sleep(s.tick)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counting = false
+	if len(s.players) == 0 {
+		return
+	}
+	s.racing = true
+	s.startedAt = s.now()
+	s.safeCells = code.Size*code.Size - code.BlackHolesCount
+	for _, p := range s.players {
+		p.opened, p.state, p.finished = 0, Racing, 0
+	}
+	s.broadcast(Message{Type: StartMessage, Code: code.String()})
+	s.broadcast(s.progress())
+}
+
+func (s *Server) handle(conn net.Conn) {
+	sc := bufio.NewScanner(conn)
+	conn.SetReadDeadline(time.Now().Add(joinTimeout))
+	p, err := s.join(sc, conn)
+	if err != nil {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		json.NewEncoder(conn).Encode(Message{Type: ErrorMessage, Error: err.Error()})
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+	defer s.leave(p)
+	for sc.Scan() {
+		var m Message
+		if err := json.Unmarshal(sc.Bytes(), &m); err != nil || m.Type != ProgressMessage {
+			continue
+		}
+		s.update(p, m)
+	}
+}
+
+// join reads the join message and adds the player to the lobby.
+func (s *Server) join(sc *bufio.Scanner, conn net.Conn) (*player, error) {
+	if !sc.Scan() {
+		return nil, errors.New("no join message")
+	}
+	var m Message
+	if err := json.Unmarshal(sc.Bytes(), &m); err != nil || m.Type != JoinMessage {
+		return nil, errors.New("join first")
+	}
+	if m.Version != Version {
+		return nil, fmt.Errorf("the host plays version %d of the race protocol, not %d", Version, m.Version)
+	}
+	name := strings.TrimSpace(m.Name)
+	if name == "" || len([]rune(name)) > MaxNameLength || strings.ContainsAny(name, " \t") {
+		return nil, fmt.Errorf("name should be 1 to %d characters without spaces", MaxNameLength)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.racing {
+		return nil, errors.New("the race is on, join after it")
+	}
+	for _, other := range s.players {
+		if other.name == name {
+			return nil, fmt.Errorf("%s is taken", name)
+		}
+	}
+	p := &player{name: name, conn: conn, send: make(chan Message, sendBuffer)}
+	go p.write()
+	s.players = append(s.players, p)
+	p.push(Message{Type: WelcomeMessage, Name: name})
+	s.broadcast(s.lobby())
+	return p, nil
+}
+
+func (s *Server) update(p *player, m Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.racing || p.state != Racing {
+		return
+	}
+	p.opened = m.Opened
+	if m.State == Won || m.State == Lost {
+		p.state = m.State
+		p.finished = s.now().Sub(s.startedAt)
+	}
+	s.broadcast(s.progress())
+	s.rankIfOver()
+}
+
+func (s *Server) leave(p *player) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(p.send)
+	p.gone = true
+	if s.racing {
+		// the player stays in the ranking
+		if p.state == Racing {
+			p.state = Left
+			p.finished = s.now().Sub(s.startedAt)
+		}
+		s.broadcast(s.progress())
+		s.rankIfOver()
+		return
+	}
+	s.remove(p)
+	s.broadcast(s.lobby())
+}
+
+func (s *Server) remove(p *player) {
+	for i, other := range s.players {
+		if other == p {
+			s.players = append(s.players[:i], s.players[i+1:]...)
+			return
+		}
+	}
+}
+
+// rankIfOver sends the ranking once nobody is racing anymore and opens the lobby of the next race.
+func (s *Server) rankIfOver() {
+	for _, p := range s.players {
+		if p.state == Racing {
+			return
+		}
+	}
+	s.broadcast(Message{Type: RankingMessage, Players: s.ranking()})
+	s.racing = false
+	for _, p := range append([]*player(nil), s.players...) {
+		if p.gone {
+			s.remove(p)
+		}
+	}
+	s.broadcast(s.lobby())
+}
+
+// ranking puts the winners first by time, then the others by how much they cleared.
+func (s *Server) ranking() []Player {
+	players := append([]*player(nil), s.players...)
+	sort.SliceStable(players, func(i, j int) bool {
+		a, b := players[i], players[j]
+		if (a.state == Won) != (b.state == Won) {
+			return a.state == Won
+		}
+		if a.state == Won {
+			return a.finished < b.finished
+		}
+		return s.percent(a) > s.percent(b)
+	})
+	ranking := make([]Player, len(players))
+	for i, p := range players {
+		ranking[i] = Player{Name: p.name, Percent: s.percent(p), State: p.state, Rank: i + 1, TimeMs: p.finished.Milliseconds()}
+	}
+	return ranking
+}
+
+func (s *Server) percent(p *player) int {
+	if s.safeCells == 0 {
+		return 0
+	}
+	return p.opened * 100 / s.safeCells
+}
+
+func (s *Server) lobby() Message {
+	m := Message{Type: LobbyMessage}
+	for _, p := range s.players {
+		m.Players = append(m.Players, Player{Name: p.name})
+	}
+	return m
+}
+
+func (s *Server) progress() Message {
+	m := Message{Type: ProgressMessage}
+	for _, p := range s.players {
+		m.Players = append(m.Players, Player{Name: p.name, Percent: s.percent(p), State: p.state})
+	}
+	return m
+}
+
+// broadcast sends the message to every connected player, s.mu has to be held.
+func (s *Server) broadcast(m Message) {
+	for _, p := range s.players {
+		if !p.gone {
+			p.push(m)
+		}
+	}
+}
+
+// push queues the message, a player lagging that far behind is disconnected.
+func (p *player) push(m Message) {
+	select {
+	case p.send <- m:
+	default:
+		p.conn.Close()
+	}
+}
+
+func (p *player) write() {
+	defer p.conn.Close()
+	enc := json.NewEncoder(p.conn)
+	for m := range p.send {
+		p.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := enc.Encode(m); err != nil {
+			return
+		}
+	}
+}
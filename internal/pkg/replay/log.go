@@ -0,0 +1,123 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"io"
+	"os"
+	"time"
+)
+
+const formatVersion = 1
+
+type Action string
+
+const (
+	Open  Action = "open"
+	Flag  Action = "flag"
+	Chord Action = "chord"
+	Undo  Action = "undo"
+	Redo  Action = "redo"
+	// Move is a cursor move, it doesn't change the board but shows the path of the player.
+	Move Action = "move"
+)
+
+type Event struct {
+	// At is the time since the recording started.
+	At     time.Duration `json:"at"`
+	Action Action        `json:"action"`
+	X      int           `json:"x"`
+	Y      int           `json:"y"`
+}
+
+// Apply plays the event on the board.
+func (e Event) Apply(b *model.Board) {
+	switch e.Action {
+	case Open:
+		b.Open(e.X, e.Y)
+	case Flag:
+		b.ToggleFlag(e.X, e.Y)
+	case Chord:
+		b.Chord(e.X, e.Y)
+	case Undo:
+		b.Undo()
+	case Redo:
+		b.Redo()
+	}
+}
+
+// Header is the first line of a log. Board is the board as it was when the recording started,
+// it carries the seed and the black holes layout.
+type Header struct {
+	Version    int              `json:"version"`
+	Difficulty model.Difficulty `json:"difficulty"`
+	Player     string           `json:"player"`
+	StartedAt  time.Time        `json:"startedAt"`
+	Board      model.Board      `json:"board"`
+}
+
+type Log struct {
+	Header Header
+	Events []Event
+}
+
+// Read loads a log written by Recorder. A truncated last line, left by a crash, is ignored.
+func Read(path string) (Log, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Log{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return Log{}, err
+		}
+		return Log{}, errors.New("empty replay file")
+	}
+	var log Log
+	if err := json.Unmarshal(scanner.Bytes(), &log.Header); err != nil {
+		return Log{}, fmt.Errorf("can't read replay header: %w", err)
+	}
+	if log.Header.Version > formatVersion {
+		return Log{}, fmt.Errorf("replay has unsupported version %d", log.Header.Version)
+	}
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			break
+		}
+		log.Events = append(log.Events, e)
+	}
+	return log, scanner.Err()
+}
+
+// BoardAt returns the board after the first n events.
+func (l Log) BoardAt(n int) model.Board {
+	b := l.Header.Board.Clone()
+	for _, e := range l.Events[:n] {
+		e.Apply(&b)
+	}
+	return b
+}
+
+// Write writes the log in the format of Recorder, i.e. to keep a replay imported from another game.
+func (l Log) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	h := l.Header
+	h.Version = formatVersion
+	if err := encoder.Encode(h); err != nil {
+		return err
+	}
+	for _, e := range l.Events {
+		if err := encoder.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
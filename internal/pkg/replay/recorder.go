@@ -0,0 +1,82 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/config"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const dirName = "replays"
+
+// DefaultDir returns the replays directory in the game config directory.
+func DefaultDir() (string, error) {
+	return config.Path(dirName)
+}
+
+// Recorder appends events to a log file as they happen, so nothing is lost if the game crashes.
+type Recorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+	start   time.Time
+	path    string
+}
+
+// NewRecorder creates a new log file in dir and writes the header to it.
+func NewRecorder(dir string, h Header) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s-%s.jsonl", h.StartedAt.Format("20060102-150405.000"), h.Difficulty.Key())
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	h.Version = formatVersion
+	r := &Recorder{file: f, encoder: json.NewEncoder(f), start: h.StartedAt, path: path}
+	if err := r.encoder.Encode(h); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+func (r *Recorder) Record(a Action, x, y int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.encoder.Encode(Event{At: time.Since(r.start), Action: a, X: x, Y: y})
+}
+
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// Latest returns the most recent log in dir.
+func Latest(dir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no replays in %s", dir)
+	}
+	// names start with the recording time, so the last one is the latest
+	latest := matches[0]
+	for _, m := range matches[1:] {
+		if m > latest {
+			latest = m
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,72 @@
+package replay
+
+import (
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRecorder_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	board, err := model.NewBoardFromLayout(3, []model.Point{model.NewPoint(1, 0), model.NewPoint(0, 2)})
+	if err != nil {
+		t.Fatalf("NewBoardFromLayout() error = %v", err)
+	}
+	r, err := NewRecorder(dir, Header{Difficulty: model.CustomDifficulty(3, 2), Player: "ann", StartedAt: time.Now(), Board: board})
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	events := []Event{
+		{Action: Move, X: 1, Y: 1},
+		{Action: Open, X: 0, Y: 0},
+		{Action: Flag, X: 1, Y: 0},
+		{Action: Chord, X: 0, Y: 0},
+	}
+	for _, e := range events {
+		if err := r.Record(e.Action, e.X, e.Y); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	// a crash in the middle of writing leaves a broken last line
+	f, _ := os.OpenFile(r.Path(), os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"at":12,"act`)
+	f.Close()
+
+	latest, err := Latest(dir)
+	if err != nil || latest != r.Path() {
+		t.Fatalf("Latest() = %v, %v, want %v", latest, err, r.Path())
+	}
+	log, err := Read(latest)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if log.Header.Player != "ann" || len(log.Events) != len(events) {
+		t.Fatalf("Read() = %+v, want %d events", log, len(events))
+	}
+	for i, e := range log.Events {
+		if e.Action != events[i].Action || e.X != events[i].X || e.Y != events[i].Y {
+			t.Errorf("event %d = %+v, want %+v", i, e, events[i])
+		}
+	}
+
+	tests := []struct {
+		events     int
+		wantOpened int
+		wantFlags  int
+	}{
+		{events: 0, wantOpened: 0, wantFlags: 0},
+		{events: 2, wantOpened: 1, wantFlags: 0},
+		{events: 3, wantOpened: 1, wantFlags: 1},
+		{events: 4, wantOpened: 3, wantFlags: 1},
+	}
+	for _, tt := range tests {
+		b := log.BoardAt(tt.events)
+		if b.GetOpenedCount() != tt.wantOpened || b.GetFlagsCount() != tt.wantFlags {
+			t.Errorf("BoardAt(%d): opened %d, flags %d, want %d, %d", tt.events, b.GetOpenedCount(), b.GetFlagsCount(), tt.wantOpened, tt.wantFlags)
+		}
+	}
+}
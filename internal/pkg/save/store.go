@@ -0,0 +1,95 @@
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/config"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const dirName = "saves"
+
+const formatVersion = 1
+
+// Autosave is the slot written when the game is quit in the middle.
+const Autosave = "autosave"
+
+// Slots is the number of slots for explicit saves.
+const Slots = 5
+
+var ErrNotFound = errors.New("no saved game")
+
+type Game struct {
+	Version    int              `json:"version"`
+	Difficulty model.Difficulty `json:"difficulty"`
+	Player     string           `json:"player"`
+	SavedAt    time.Time        `json:"savedAt"`
+	Board      model.Board      `json:"board"`
+}
+
+// Store keeps every slot in its own JSON file.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) Store {
+	return Store{dir: dir}
+}
+
+// DefaultStore returns the store in the game config directory.
+func DefaultStore() (Store, error) {
+	path, err := config.Path(dirName)
+	if err != nil {
+		return Store{}, err
+	}
+	return NewStore(path), nil
+}
+
+// SlotName returns the name of the explicit save slot i, starting from 1.
+func SlotName(i int) string {
+	return fmt.Sprintf("slot-%d", i)
+}
+
+func (s Store) Save(slot string, g Game) error {
+	g.Version = formatVersion
+	b, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	return config.WriteFileAtomic(s.path(slot), b)
+}
+
+// Load returns ErrNotFound if nothing is saved in the slot.
+func (s Store) Load(slot string) (Game, error) {
+	b, err := os.ReadFile(s.path(slot))
+	if errors.Is(err, os.ErrNotExist) {
+		return Game{}, ErrNotFound
+	}
+	if err != nil {
+		return Game{}, err
+	}
+	var g Game
+	if err := json.Unmarshal(b, &g); err != nil {
+		return Game{}, fmt.Errorf("can't read saved game %s: %w", slot, err)
+	}
+	if g.Version > formatVersion {
+		return Game{}, fmt.Errorf("saved game %s has unsupported version %d", slot, g.Version)
+	}
+	return g, nil
+}
+
+func (s Store) Delete(slot string) error {
+	err := os.Remove(s.path(slot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s Store) path(slot string) string {
+	return filepath.Join(s.dir, slot+".json")
+}
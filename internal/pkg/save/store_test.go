@@ -0,0 +1,41 @@
+package save
+
+import (
+	"errors"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestStore_SaveLoad(t *testing.T) {
+	s := NewStore(t.TempDir())
+	board, err := model.NewBoard(model.RandomCoordinatesProvider{Seed: 42}, 8, 10)
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+	board.ToggleFlag(3, 3)
+
+	if _, err := s.Load(Autosave); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load() of an empty slot error = %v, want %v", err, ErrNotFound)
+	}
+	saved := Game{Difficulty: model.Easy, Player: "ann", SavedAt: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), Board: board}
+	if err := s.Save(SlotName(2), saved); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := s.Load(SlotName(2))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.Difficulty != model.Easy || got.Player != "ann" || !got.SavedAt.Equal(saved.SavedAt) ||
+		got.Board.GetSeed() != 42 || !got.Board.IsFlagged(3, 3) {
+		t.Errorf("Load() = %+v, want %+v", got, saved)
+	}
+
+	if err := s.Delete(SlotName(2)); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Load(SlotName(2)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load() after Delete() error = %v, want %v", err, ErrNotFound)
+	}
+}
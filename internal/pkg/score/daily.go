@@ -0,0 +1,100 @@
+package score
+
+import (
+	"errors"
+	"time"
+)
+
+// DayLayout formats the days of the daily challenge.
+const DayLayout = "2006-01-02"
+
+// ErrDailyPlayed is returned when the player already made the official attempt of the day.
+var ErrDailyPlayed = errors.New("the daily challenge was already played")
+
+// dailyStats keeps the lifetime statistics of all daily challenges together.
+const dailyStats = "daily"
+
+// DailyAttempt is the official attempt of a player at the daily challenge, only the first game of the day counts.
+type DailyAttempt struct {
+	Won   bool          `json:"won"`
+	Time  time.Duration `json:"time"`
+	Moves int           `json:"moves"`
+	Date  time.Time     `json:"date"`
+}
+
+type DailyResult struct {
+	Result
+	// Streak is the number of daily challenges won in a row, up to this one.
+	Streak int
+}
+
+// DailyKey is the high score table of the day.
+func DailyKey(day string) string {
+	return dailyStats + "-" + day
+}
+
+// RecordDaily records the official attempt of the day, it fails with ErrDailyPlayed for any later game.
+func (s Store) RecordDaily(day string, o Outcome) (DailyResult, error) {
+	res := DailyResult{}
+	played := false
+	err := s.update(func(d *data) {
+		if _, played = d.Daily[day][o.Player]; played {
+			return
+		}
+		if d.Daily[day] == nil {
+			d.Daily[day] = map[string]DailyAttempt{}
+		}
+		d.Daily[day][o.Player] = DailyAttempt{Won: o.Won, Time: o.Time, Moves: o.Moves, Date: o.Date}
+
+		stats := d.Stats[dailyStats]
+		stats.add(o)
+		d.Stats[dailyStats] = stats
+		if o.Won {
+			res.Result = d.add(DailyKey(day), o.Entry)
+		}
+		res.Streak = d.dailyStreak(day, o.Player)
+	})
+	if err == nil && played {
+		err = ErrDailyPlayed
+	}
+	return res, err
+}
+
+// DailyAttempt returns the official attempt of the player on the day, ok is false if there was none.
+func (s Store) DailyAttempt(day, player string) (a DailyAttempt, ok bool, err error) {
+	d, err := s.read()
+	if err != nil {
+		return DailyAttempt{}, false, err
+	}
+	a, ok = d.Daily[day][player]
+	return a, ok, nil
+}
+
+// DailyStreak returns the number of daily challenges won in a row by the player. The streak goes on
+// until the day is played, so it counts up to the day before if the day wasn't played yet.
+func (s Store) DailyStreak(day, player string) (int, error) {
+	d, err := s.read()
+	if err != nil {
+		return 0, err
+	}
+	return d.dailyStreak(day, player), nil
+}
+
+func (d *data) dailyStreak(day, player string) int {
+	date, err := time.Parse(DayLayout, day)
+	if err != nil {
+		return 0
+	}
+	if _, ok := d.Daily[day][player]; !ok {
+		date = date.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for {
+		a, ok := d.Daily[date.Format(DayLayout)][player]
+		if !ok || !a.Won {
+			return streak
+		}
+		streak++
+		date = date.AddDate(0, 0, -1)
+	}
+}
@@ -0,0 +1,56 @@
+package score
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_RecordDaily(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), FileName))
+	won := Outcome{Won: true, BoardSize: 3, CellsOpened: 7, Entry: Entry{Player: "ann", Time: 4 * time.Second}}
+	lost := Outcome{Won: false, BoardSize: 3, CellsOpened: 2, Entry: Entry{Player: "ann"}}
+	tests := []struct {
+		day        string
+		outcome    Outcome
+		wantStreak int
+		wantErr    error
+	}{
+		{day: "2023-02-27", outcome: won, wantStreak: 1},
+		{day: "2023-02-28", outcome: lost, wantStreak: 0},
+		{day: "2023-03-01", outcome: won, wantStreak: 1},
+		{day: "2023-03-01", outcome: won, wantErr: ErrDailyPlayed},
+		{day: "2023-03-02", outcome: won, wantStreak: 2},
+		{day: "2023-03-04", outcome: won, wantStreak: 1},
+	}
+	for _, tt := range tests {
+		res, err := s.RecordDaily(tt.day, tt.outcome)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("RecordDaily(%s) error = %v, want %v", tt.day, err, tt.wantErr)
+		}
+		if err == nil && res.Streak != tt.wantStreak {
+			t.Errorf("RecordDaily(%s) streak = %d, want %d", tt.day, res.Streak, tt.wantStreak)
+		}
+	}
+
+	if a, ok, err := s.DailyAttempt("2023-02-28", "ann"); err != nil || !ok || a.Won {
+		t.Errorf("DailyAttempt() = %+v, %v, %v, want a lost attempt", a, ok, err)
+	}
+	if _, ok, _ := s.DailyAttempt("2023-03-03", "ann"); ok {
+		t.Errorf("DailyAttempt() found an attempt on a day not played")
+	}
+	if streak, _ := s.DailyStreak("2023-03-05", "ann"); streak != 1 {
+		t.Errorf("DailyStreak() before playing = %d, want 1", streak)
+	}
+	if streak, _ := s.DailyStreak("2023-03-06", "ann"); streak != 0 {
+		t.Errorf("DailyStreak() after a missed day = %d, want 0", streak)
+	}
+	all, _ := s.Stats()
+	if st := all["daily"]; st.Played != 5 || st.Won != 4 {
+		t.Errorf("Stats()[daily] = %+v, want 5 played and 4 won", st)
+	}
+	if top, _ := s.Top(DailyKey("2023-03-01")); len(top) != 1 {
+		t.Errorf("Top() = %+v, want the official attempt only", top)
+	}
+}
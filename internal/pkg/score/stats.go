@@ -0,0 +1,97 @@
+package score
+
+import "time"
+
+// maxWonTimes limits how many completion times are kept for the histogram.
+const maxWonTimes = 1000
+
+type Stats struct {
+	Played        int `json:"played"`
+	Won           int `json:"won"`
+	Lost          int `json:"lost"`
+	CurrentStreak int `json:"currentStreak"`
+	BestStreak    int `json:"bestStreak"`
+	CellsOpened   int `json:"cellsOpened"`
+	// TotalWonTime sums up the time of the won games only.
+	TotalWonTime time.Duration `json:"totalWonTime"`
+	// WonTimes are the completion times of the most recent won games.
+	WonTimes []time.Duration `json:"wonTimes"`
+	// LossHeatmap counts losses by position, indexed as [y][x].
+	LossHeatmap [][]int `json:"lossHeatmap"`
+}
+
+func (s Stats) AverageTime() time.Duration {
+	if s.Won == 0 {
+		return 0
+	}
+	return s.TotalWonTime / time.Duration(s.Won)
+}
+
+func (s Stats) WinRate() float64 {
+	if s.Played == 0 {
+		return 0
+	}
+	return float64(s.Won) / float64(s.Played)
+}
+
+// Outcome is a finished game as recorded by RecordGame.
+type Outcome struct {
+	Entry
+	Won         bool
+	BoardSize   int
+	CellsOpened int
+	LostX       int
+	LostY       int
+}
+
+func (s *Stats) add(o Outcome) {
+	s.Played++
+	s.CellsOpened += o.CellsOpened
+	if o.Won {
+		s.Won++
+		s.CurrentStreak++
+		if s.CurrentStreak > s.BestStreak {
+			s.BestStreak = s.CurrentStreak
+		}
+		s.TotalWonTime += o.Time
+		s.WonTimes = append(s.WonTimes, o.Time)
+		if len(s.WonTimes) > maxWonTimes {
+			s.WonTimes = s.WonTimes[len(s.WonTimes)-maxWonTimes:]
+		}
+		return
+	}
+	s.Lost++
+	s.CurrentStreak = 0
+	if len(s.LossHeatmap) != o.BoardSize {
+		s.LossHeatmap = make([][]int, o.BoardSize)
+		for y := range s.LossHeatmap {
+			s.LossHeatmap[y] = make([]int, o.BoardSize)
+		}
+	}
+	if o.LostY >= 0 && o.LostY < o.BoardSize && o.LostX >= 0 && o.LostX < o.BoardSize {
+		s.LossHeatmap[o.LostY][o.LostX]++
+	}
+}
+
+// RecordGame updates the statistics of the difficulty key and adds won games to the high scores.
+func (s Store) RecordGame(key string, o Outcome) (Result, error) {
+	res := Result{}
+	err := s.update(func(d *data) {
+		stats := d.Stats[key]
+		stats.add(o)
+		d.Stats[key] = stats
+		if o.Won {
+			res = d.add(key, o.Entry)
+		}
+	})
+	return res, err
+}
+
+// Stats returns the statistics of all difficulties played so far.
+func (s Store) Stats() (map[string]Stats, error) {
+	d, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return d.Stats, nil
+}
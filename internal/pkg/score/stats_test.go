@@ -0,0 +1,51 @@
+package score
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStore_RecordGame(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), FileName))
+	outcomes := []Outcome{
+		{Won: true, BoardSize: 3, CellsOpened: 7, Entry: Entry{Player: "ann", Time: 4 * time.Second}},
+		{Won: true, BoardSize: 3, CellsOpened: 7, Entry: Entry{Player: "ann", Time: 2 * time.Second}},
+		{Won: false, BoardSize: 3, CellsOpened: 3, LostX: 2, LostY: 0},
+		{Won: true, BoardSize: 3, CellsOpened: 7, Entry: Entry{Player: "ann", Time: 6 * time.Second}},
+		{Won: false, BoardSize: 3, CellsOpened: 0, LostX: 2, LostY: 0},
+		{Won: false, BoardSize: 3, CellsOpened: 1, LostX: 1, LostY: 2},
+	}
+	for _, o := range outcomes {
+		if _, err := s.RecordGame("easy", o); err != nil {
+			t.Fatalf("RecordGame() error = %v", err)
+		}
+	}
+
+	all, err := s.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	want := Stats{
+		Played:        6,
+		Won:           3,
+		Lost:          3,
+		CurrentStreak: 0,
+		BestStreak:    2,
+		CellsOpened:   25,
+		TotalWonTime:  12 * time.Second,
+		WonTimes:      []time.Duration{4 * time.Second, 2 * time.Second, 6 * time.Second},
+		LossHeatmap:   [][]int{{0, 0, 2}, {0, 0, 0}, {0, 1, 0}},
+	}
+	if got := all["easy"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Stats()[easy] = %+v, want %+v", got, want)
+	}
+	if got := all["easy"].AverageTime(); got != 4*time.Second {
+		t.Errorf("AverageTime() = %v, want 4s", got)
+	}
+	top, _ := s.Top("easy")
+	if len(top) != 3 || top[0].Time != 2*time.Second {
+		t.Errorf("Top() = %+v, want 3 won games with the best of 2s", top)
+	}
+}
@@ -0,0 +1,198 @@
+package score
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/config"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const FileName = "scores.json"
+
+const version = 1
+
+// MaxEntries is the size of the high score table of every difficulty.
+const MaxEntries = 10
+
+type Entry struct {
+	Player  string        `json:"player"`
+	Time    time.Duration `json:"time"`
+	Moves   int           `json:"moves"`
+	ThreeBV int           `json:"3bv"`
+	Seed    int64         `json:"seed"`
+	Date    time.Time     `json:"date"`
+}
+
+type data struct {
+	Version int                `json:"version"`
+	Scores  map[string][]Entry `json:"scores"`
+	// Bests keeps the best entry of every player, even if it fell out of the high score table.
+	Bests map[string]map[string]Entry `json:"bests"`
+	Stats map[string]Stats            `json:"stats"`
+	// Daily keeps the official daily challenge attempts by day and player.
+	Daily map[string]map[string]DailyAttempt `json:"daily"`
+}
+
+// Store keeps the scores in a JSON file shared between all running games.
+type Store struct {
+	path string
+}
+
+// mu serializes access within a process, the lock file does it between processes.
+var mu sync.Mutex
+
+func NewStore(path string) Store {
+	return Store{path: path}
+}
+
+// DefaultStore returns the store in the game config directory.
+func DefaultStore() (Store, error) {
+	path, err := config.Path(FileName)
+	if err != nil {
+		return Store{}, err
+	}
+	return NewStore(path), nil
+}
+
+func (s Store) Path() string {
+	return s.path
+}
+
+type Result struct {
+	// Rank is the 1-based position in the high score table, 0 if the entry didn't get there.
+	Rank         int
+	PersonalBest bool
+}
+
+// Add records a won game for the difficulty key.
+func (s Store) Add(key string, e Entry) (Result, error) {
+	res := Result{}
+	err := s.update(func(d *data) {
+		res = d.add(key, e)
+	})
+	return res, err
+}
+
+func (d *data) add(key string, e Entry) Result {
+	res := Result{}
+	entries := append(d.Scores[key], e)
+	sortEntries(entries)
+	for i, entry := range entries {
+		if entry == e {
+			res.Rank = i + 1
+			break
+		}
+	}
+	if len(entries) > MaxEntries {
+		entries = entries[:MaxEntries]
+	}
+	if res.Rank > MaxEntries {
+		res.Rank = 0
+	}
+	d.Scores[key] = entries
+
+	if d.Bests[key] == nil {
+		d.Bests[key] = map[string]Entry{}
+	}
+	if best, ok := d.Bests[key][e.Player]; !ok || better(e, best) {
+		d.Bests[key][e.Player] = e
+		res.PersonalBest = true
+	}
+	return res
+}
+
+// Top returns the high score table of the difficulty key, the best first.
+func (s Store) Top(key string) ([]Entry, error) {
+	d, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return d.Scores[key], nil
+}
+
+func (s Store) PersonalBest(key, player string) (Entry, bool, error) {
+	d, err := s.read()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	e, ok := d.Bests[key][player]
+	return e, ok, nil
+}
+
+func (s Store) read() (data, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return s.load()
+}
+
+func (s Store) update(f func(d *data)) error {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := config.Lock(s.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	d, err := s.load()
+	if err != nil {
+		return err
+	}
+	f(&d)
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return config.WriteFileAtomic(s.path, b)
+}
+
+func (s Store) load() (data, error) {
+	d := data{Version: version, Scores: map[string][]Entry{}, Bests: map[string]map[string]Entry{}, Stats: map[string]Stats{},
+		Daily: map[string]map[string]DailyAttempt{}}
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return d, err
+	}
+	if err := json.Unmarshal(b, &d); err != nil {
+		return d, fmt.Errorf("can't read scores from %s: %w", s.path, err)
+	}
+	if d.Version > version {
+		return d, fmt.Errorf("scores file %s has unsupported version %d", s.path, d.Version)
+	}
+	if d.Scores == nil {
+		d.Scores = map[string][]Entry{}
+	}
+	if d.Bests == nil {
+		d.Bests = map[string]map[string]Entry{}
+	}
+	if d.Stats == nil {
+		d.Stats = map[string]Stats{}
+	}
+	if d.Daily == nil {
+		d.Daily = map[string]map[string]DailyAttempt{}
+	}
+	return d, nil
+}
+
+func sortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return better(entries[i], entries[j])
+	})
+}
+
+func better(a, b Entry) bool {
+	if a.Time != b.Time {
+		return a.Time < b.Time
+	}
+	if a.Moves != b.Moves {
+		return a.Moves < b.Moves
+	}
+	return a.Date.Before(b.Date)
+}
@@ -0,0 +1,112 @@
+package score
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore_Add(t *testing.T) {
+	date := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		existing []Entry
+		add      Entry
+		want     Result
+	}{
+		{
+			name: "first entry",
+			add:  Entry{Player: "ann", Time: 10 * time.Second, Date: date},
+			want: Result{Rank: 1, PersonalBest: true},
+		},
+		{
+			name:     "slower than own best",
+			existing: []Entry{{Player: "ann", Time: 10 * time.Second, Date: date}},
+			add:      Entry{Player: "ann", Time: 12 * time.Second, Date: date},
+			want:     Result{Rank: 2, PersonalBest: false},
+		},
+		{
+			name:     "personal best of another player",
+			existing: []Entry{{Player: "ann", Time: 10 * time.Second, Date: date}},
+			add:      Entry{Player: "bob", Time: 12 * time.Second, Date: date},
+			want:     Result{Rank: 2, PersonalBest: true},
+		},
+		{
+			name:     "fewer moves wins a tie",
+			existing: []Entry{{Player: "ann", Time: 10 * time.Second, Moves: 20, Date: date}},
+			add:      Entry{Player: "ann", Time: 10 * time.Second, Moves: 15, Date: date},
+			want:     Result{Rank: 1, PersonalBest: true},
+		},
+		{
+			name:     "out of the table",
+			existing: entries("ann", MaxEntries, date),
+			add:      Entry{Player: "bob", Time: time.Hour, Date: date},
+			want:     Result{Rank: 0, PersonalBest: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStore(filepath.Join(t.TempDir(), FileName))
+			for _, e := range tt.existing {
+				if _, err := s.Add("easy", e); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+			}
+
+			got, err := s.Add("easy", tt.add)
+			if err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Add() = %+v, want %+v", got, tt.want)
+			}
+			top, _ := s.Top("easy")
+			if len(top) > MaxEntries {
+				t.Errorf("Top() returned %d entries, want at most %d", len(top), MaxEntries)
+			}
+			if other, _ := s.Top("hard"); len(other) != 0 {
+				t.Errorf("Top(hard) = %v, want no entries", other)
+			}
+		})
+	}
+}
+
+func TestStore_ConcurrentAdd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	var wg sync.WaitGroup
+	for i := 0; i < MaxEntries; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// separate stores with the same path behave like separate game processes
+			s := NewStore(path)
+			if _, err := s.Add("easy", Entry{Player: fmt.Sprint(i), Time: time.Duration(i) * time.Second}); err != nil {
+				t.Errorf("Add() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	top, err := NewStore(path).Top("easy")
+	if err != nil {
+		t.Fatalf("Top() error = %v", err)
+	}
+	if len(top) != MaxEntries {
+		t.Fatalf("Top() returned %d entries, want %d", len(top), MaxEntries)
+	}
+	for i, e := range top {
+		if e.Time != time.Duration(i)*time.Second {
+			t.Errorf("Top()[%d] = %+v, want time %ds", i, e, i)
+		}
+	}
+}
+
+func entries(player string, n int, date time.Time) []Entry {
+	res := make([]Entry, n)
+	for i := range res {
+		res[i] = Entry{Player: player, Time: time.Duration(i+1) * time.Second, Date: date}
+	}
+	return res
+}
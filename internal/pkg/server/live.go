@@ -0,0 +1,197 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/gorilla/websocket"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"net/http"
+	"time"
+)
+
+const (
+	// sendBuffer is how many messages a client may lag behind before it's disconnected.
+	sendBuffer   = 64
+	writeTimeout = 10 * time.Second
+	tick         = time.Second
+)
+
+// upgrader keeps the default same origin check, pages of other sites can't play for the visitors.
+var upgrader = websocket.Upgrader{}
+
+// Message is pushed to the live clients as JSON, the type is one of:
+//
+//	game     the state, sent on connecting
+//	started  the first move started the timer
+//	opened   the cells opened by a move, the cascade included
+//	closed   the cells closed again
+//	flagged  the cell flagged or unflagged
+//	state    the game state changed from and to
+//	timer    the elapsed time, every second while the game runs
+//	error    the command of the client went wrong
+type Message struct {
+	Type      string `json:"type"`
+	Game      *State `json:"game,omitempty"`
+	Cells     []Cell `json:"cells,omitempty"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	ElapsedMs int64  `json:"elapsedMs,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Command is a move sent by a playing client, the action is open, flag or chord.
+type Command struct {
+	Action string `json:"action"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+}
+
+// client is a live connection, send is closed once it's disconnected from the game.
+type client struct {
+	send      chan Message
+	spectator bool
+}
+
+func (s *Server) live(w http.ResponseWriter, r *http.Request, g *Game) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has replied with the error
+		return
+	}
+	c := &client{send: make(chan Message, sendBuffer), spectator: r.URL.Query().Get("spectate") == "true"}
+	g.mu.Lock()
+	st := state(g, nil)
+	c.send <- Message{Type: "game", Game: &st}
+	g.clients[c] = true
+	g.mu.Unlock()
+
+	go c.write(conn, g)
+	s.read(conn, g, c)
+}
+
+// read plays the commands of the client until it goes away.
+func (s *Server) read(conn *websocket.Conn, g *Game, c *client) {
+	defer func() {
+		g.mu.Lock()
+		g.disconnect(c)
+		g.mu.Unlock()
+	}()
+	conn.SetReadLimit(maxBodySize)
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		// the moves keep the game from expiring like the requests do
+		if _, ok := s.store.Get(g.ID); !ok {
+			return
+		}
+		var cmd Command
+		err = json.Unmarshal(data, &cmd)
+		g.mu.Lock()
+		switch {
+		case err != nil:
+			g.push(c, Message{Type: "error", Error: "invalid command: " + err.Error()})
+		case c.spectator:
+			g.push(c, Message{Type: "error", Error: "spectators can't play"})
+		default:
+			// the changes reach every client through broadcast
+			if _, _, err := play(g, cmd.Action, cmd.X, cmd.Y); err != nil {
+				g.push(c, Message{Type: "error", Error: err.Error()})
+			}
+		}
+		g.mu.Unlock()
+	}
+}
+
+// write sends the messages and the timer to the client until it's disconnected.
+func (c *client) write(conn *websocket.Conn, g *Game) {
+	ticker := time.NewTicker(tick)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+	for {
+		select {
+		case m, ok := <-c.send:
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if err := conn.WriteJSON(m); err != nil {
+				return
+			}
+		case <-ticker.C:
+			g.mu.Lock()
+			running := g.board.GetState() == model.InProgress && g.board.GetMoves() > 0
+			elapsed := g.board.GetElapsed()
+			g.mu.Unlock()
+			if !running {
+				continue
+			}
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteJSON(Message{Type: "timer", ElapsedMs: elapsed.Milliseconds()}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// broadcast pushes the board event to the clients, it's called by the board with g.mu held.
+func (g *Game) broadcast(e model.Event) {
+	m := Message{}
+	switch e := e.(type) {
+	case model.GameStarted:
+		m.Type = "started"
+	case model.CellsOpened:
+		m.Type = "opened"
+		snapshot := g.board.Snapshot()
+		// the other listeners get the event too, the cells are sorted in a copy
+		cells := append([]model.Point(nil), e.Cells...)
+		model.SortPoints(cells)
+		for _, p := range cells {
+			m.Cells = append(m.Cells, Cell{X: p.X(), Y: p.Y(), Value: string(snapshot.Get(p.X(), p.Y()).Symbol())})
+		}
+	case model.CellsClosed:
+		m.Type = "closed"
+		cells := append([]model.Point(nil), e.Cells...)
+		model.SortPoints(cells)
+		for _, p := range cells {
+			m.Cells = append(m.Cells, Cell{X: p.X(), Y: p.Y(), Value: string(model.ClosedSymbol)})
+		}
+	case model.CellFlagged:
+		m.Type = "flagged"
+		value := model.ClosedSymbol
+		if e.Flagged {
+			value = model.FlagSymbol
+		}
+		m.Cells = []Cell{{X: e.X, Y: e.Y, Value: string(value)}}
+	case model.StateChanged:
+		m.Type, m.From, m.To = "state", e.From.String(), e.To.String()
+	default:
+		return
+	}
+	for c := range g.clients {
+		g.push(c, m)
+	}
+}
+
+// push queues the message for the client, a client lagging behind is disconnected. g.mu has to be held.
+func (g *Game) push(c *client, m Message) {
+	if !g.clients[c] {
+		return
+	}
+	select {
+	case c.send <- m:
+	default:
+		g.disconnect(c)
+	}
+}
+
+// disconnect closes the send channel once, the writer then closes the connection. g.mu has to be held.
+func (g *Game) disconnect(c *client) {
+	if g.clients[c] {
+		delete(g.clients, c)
+		close(c.send)
+	}
+}
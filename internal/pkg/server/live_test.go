@@ -0,0 +1,163 @@
+package server
+
+import (
+	"github.com/gorilla/websocket"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func dial(t *testing.T, ts *httptest.Server, id, query string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/games/"+id+"/ws"+query, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// receive returns the next message but the timer ticks.
+func receive(t *testing.T, conn *websocket.Conn) Message {
+	t.Helper()
+	for {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var m Message
+		if err := conn.ReadJSON(&m); err != nil {
+			t.Fatalf("ReadJSON() error = %v", err)
+		}
+		if m.Type != "timer" {
+			return m
+		}
+	}
+}
+
+func TestServer_live(t *testing.T) {
+	tests := []struct {
+		name string
+		// commands are sent by the player, or the spectator when prefixed with spectator:
+		commands []string
+		want     []Message
+	}{
+		{
+			name:     "cascade",
+			commands: []string{`{"action":"open","x":2,"y":0}`},
+			want: []Message{
+				{Type: "started"},
+				{Type: "opened", Cells: []Cell{
+					{1, 0, "1"}, {2, 0, "0"}, {3, 0, "0"},
+					{1, 1, "1"}, {2, 1, "0"}, {3, 1, "0"},
+					{1, 2, "1"}, {2, 2, "1"}, {3, 2, "1"},
+				}},
+			},
+		},
+		{
+			name:     "flags",
+			commands: []string{`{"action":"flag","x":0,"y":0}`, `{"action":"flag","x":0,"y":0}`},
+			want: []Message{
+				{Type: "flagged", Cells: []Cell{{0, 0, "F"}}},
+				{Type: "flagged", Cells: []Cell{{0, 0, "?"}}},
+			},
+		},
+		{
+			name:     "lost",
+			commands: []string{`{"action":"open","x":0,"y":0}`},
+			want: []Message{
+				{Type: "started"},
+				{Type: "opened", Cells: []Cell{{0, 0, "*"}}},
+				{Type: "state", From: "in_progress", To: "lost"},
+			},
+		},
+		{
+			name:     "spectator can't play",
+			commands: []string{`spectator:{"action":"open","x":0,"y":0}`, `{"action":"flag","x":1,"y":1}`},
+			want:     []Message{{Type: "flagged", Cells: []Cell{{1, 1, "F"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, id := newTestServer(t)
+			ts := httptest.NewServer(s)
+			defer ts.Close()
+			player := dial(t, ts, id, "")
+			spectator := dial(t, ts, id, "?spectate=true")
+			for _, conn := range []*websocket.Conn{player, spectator} {
+				if m := receive(t, conn); m.Type != "game" || m.Game == nil || m.Game.ID != id {
+					t.Fatalf("first message = %+v, want the game", m)
+				}
+			}
+
+			for _, command := range tt.commands {
+				conn := player
+				if strings.HasPrefix(command, "spectator:") {
+					conn = spectator
+					command = strings.TrimPrefix(command, "spectator:")
+				}
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(command)); err != nil {
+					t.Fatalf("WriteMessage() error = %v", err)
+				}
+				if conn == spectator {
+					if m := receive(t, spectator); m.Type != "error" {
+						t.Errorf("spectator move answer = %+v, want an error", m)
+					}
+				}
+			}
+			for _, conn := range []*websocket.Conn{player, spectator} {
+				for _, want := range tt.want {
+					if got := receive(t, conn); !reflect.DeepEqual(got, want) {
+						t.Errorf("message = %+v, want %+v", got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestServer_liveErrors(t *testing.T) {
+	s, id := newTestServer(t)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	player := dial(t, ts, id, "")
+	receive(t, player)
+
+	for _, command := range []string{`{"action":"jump","x":0,"y":0}`, `{"action":"open","x":9,"y":0}`, `open`} {
+		if err := player.WriteMessage(websocket.TextMessage, []byte(command)); err != nil {
+			t.Fatalf("WriteMessage() error = %v", err)
+		}
+		if m := receive(t, player); m.Type != "error" || m.Error == "" {
+			t.Errorf("answer to %s = %+v, want an error", command, m)
+		}
+	}
+}
+
+func TestServer_liveFollowsRequests(t *testing.T) {
+	s, id := newTestServer(t)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	spectator := dial(t, ts, id, "?spectate=true")
+	receive(t, spectator)
+
+	resp, err := http.Post(ts.URL+"/games/"+id+"/flag", "application/json", strings.NewReader(`{"x":3,"y":3}`))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+	want := Message{Type: "flagged", Cells: []Cell{{3, 3, "F"}}}
+	if got := receive(t, spectator); !reflect.DeepEqual(got, want) {
+		t.Errorf("message = %+v, want %+v", got, want)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/games/"+id, nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Delete error = %v", err)
+	}
+	resp.Body.Close()
+	spectator.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := spectator.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("ReadMessage() after delete error = %v, want a normal close", err)
+	}
+}
@@ -0,0 +1,301 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBodySize is far above any valid request, it keeps the server from reading huge bodies.
+const maxBodySize = 1 << 16
+
+type Config struct {
+	// TTL is how long a game is kept after its last request.
+	TTL      time.Duration
+	MaxGames int
+}
+
+// Server is the HTTP JSON API of the game. The responses show only what a player sees,
+// the black holes are revealed by the result once the game is over.
+type Server struct {
+	store *Store
+}
+
+func New(cfg Config) *Server {
+	return &Server{store: NewStore(cfg.TTL, cfg.MaxGames)}
+}
+
+// CreateRequest creates a game of the difficulty (easy, medium, hard or daily), the custom size and black holes count
+// or the code.
+// A zero seed picks a random one, a zero generator the latest one.
+type CreateRequest struct {
+	Difficulty      string          `json:"difficulty"`
+	Size            int             `json:"size"`
+	BlackHolesCount int             `json:"blackHolesCount"`
+	Seed            int64           `json:"seed"`
+	Generator       model.Generator `json:"generator"`
+	Code            string          `json:"code"`
+}
+
+type MoveRequest struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type Cell struct {
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Value string `json:"value"`
+}
+
+// State is the game as the player sees it. Board rows are snapshot symbols: ? closed, F flag, * the opened black hole
+// and the numbers. Changed lists the cells changed by the move, the code is only set once the game is over.
+type State struct {
+	ID              string   `json:"id"`
+	Size            int      `json:"size"`
+	BlackHolesCount int      `json:"blackHolesCount"`
+	State           string   `json:"state"`
+	Moves           int      `json:"moves"`
+	Flags           int      `json:"flags"`
+	ElapsedMs       int64    `json:"elapsedMs"`
+	Code            string   `json:"code,omitempty"`
+	Board           []string `json:"board"`
+	Changed         []Cell   `json:"changed,omitempty"`
+}
+
+// Result is the finished game with the black holes revealed, layout rows are in the layout text format.
+type Result struct {
+	ID         string   `json:"id"`
+	State      string   `json:"state"`
+	Won        bool     `json:"won"`
+	TimeMs     int64    `json:"timeMs"`
+	Moves      int      `json:"moves"`
+	ThreeBV    int      `json:"threeBV"`
+	LostAt     *Cell    `json:"lostAt,omitempty"`
+	BlackHoles []Cell   `json:"blackHoles"`
+	Layout     []string `json:"layout"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP routes:
+//
+//	POST   /games               create a game
+//	GET    /games/{id}          the visible state
+//	DELETE /games/{id}          forget the game
+//	POST   /games/{id}/open     open, flag or chord the cell of the body
+//	POST   /games/{id}/flag
+//	POST   /games/{id}/chord
+//	GET    /games/{id}/result   the result once the game is over
+//	GET    /games/{id}/ws       the WebSocket to play live, or to watch with ?spectate=true
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "games" || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if len(parts) == 1 {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.create(w, r)
+		return
+	}
+
+	g, ok := s.store.Get(parts[1])
+	if !ok {
+		writeError(w, http.StatusNotFound, "game not found, it may have expired")
+		return
+	}
+	action := ""
+	if len(parts) == 3 {
+		action = parts[2]
+	}
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		writeJSON(w, http.StatusOK, state(g, nil))
+	case action == "" && r.Method == http.MethodDelete:
+		s.store.Delete(g.ID)
+		w.WriteHeader(http.StatusNoContent)
+	case (action == "open" || action == "flag" || action == "chord") && r.Method == http.MethodPost:
+		move(w, r, g, action)
+	case action == "result" && r.Method == http.MethodGet:
+		result(w, g)
+	case action == "ws" && r.Method == http.MethodGet:
+		s.live(w, r, g)
+	case action == "" || action == "open" || action == "flag" || action == "chord" || action == "result" || action == "ws":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var req CreateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	board, err := model.BoardOptions{Difficulty: req.Difficulty, Size: req.Size, BlackHolesCount: req.BlackHolesCount, Seed: req.Seed,
+		Generator: req.Generator, Code: req.Code}.NewBoard(time.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	g, err := s.store.Add(board)
+	if errors.Is(err, ErrFull) {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	writeJSON(w, http.StatusCreated, state(g, nil))
+}
+
+func move(w http.ResponseWriter, r *http.Request, g *Game, action string) {
+	var req MoveRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	st, status, err := play(g, action, req.X, req.Y)
+	if err != nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, st)
+}
+
+// play makes the move and returns the state with the changed cells, or the error with its HTTP status.
+// g.mu has to be held.
+func play(g *Game, action string, x, y int) (State, int, error) {
+	b := &g.board
+	if x < 0 || x >= b.GetSize() || y < 0 || y >= b.GetSize() {
+		return State{}, http.StatusBadRequest, fmt.Errorf("%d,%d is outside of the board", x, y)
+	}
+	if b.GetState() != model.InProgress {
+		return State{}, http.StatusConflict, fmt.Errorf("game is %s", b.GetState())
+	}
+
+	changed := map[model.Point]bool{}
+	unsubscribe := b.Subscribe(func(e model.Event) {
+		switch e := e.(type) {
+		case model.CellsOpened:
+			for _, p := range e.Cells {
+				changed[p] = true
+			}
+		case model.CellFlagged:
+			changed[model.NewPoint(e.X, e.Y)] = true
+		}
+	})
+	switch action {
+	case "open":
+		b.Open(x, y)
+	case "flag":
+		b.ToggleFlag(x, y)
+	case "chord":
+		b.Chord(x, y)
+	default:
+		unsubscribe()
+		return State{}, http.StatusBadRequest, fmt.Errorf("unknown action %q, want open, flag or chord", action)
+	}
+	unsubscribe()
+	return state(g, changed), http.StatusOK, nil
+}
+
+func result(w http.ResponseWriter, g *Game) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b := &g.board
+	if b.GetState() == model.InProgress {
+		writeError(w, http.StatusConflict, "game is in progress, the result comes once it's over")
+		return
+	}
+	res := Result{
+		ID:      g.ID,
+		State:   b.GetState().String(),
+		Won:     b.GetState() == model.Won,
+		TimeMs:  b.GetElapsed().Milliseconds(),
+		Moves:   b.GetMoves(),
+		ThreeBV: b.Metrics().ThreeBV,
+		Layout:  rows(b.Layout()),
+	}
+	if x, y, ok := b.GetLostAt(); ok {
+		res.LostAt = &Cell{X: x, Y: y, Value: string(model.BlackHoleSymbol)}
+	}
+	for _, p := range b.GetBlackHoles() {
+		res.BlackHoles = append(res.BlackHoles, Cell{X: p.X(), Y: p.Y(), Value: string(model.BlackHoleSymbol)})
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// state returns what the player sees, g.mu has to be held.
+func state(g *Game, changed map[model.Point]bool) State {
+	b := &g.board
+	snapshot := b.Snapshot()
+	st := State{
+		ID:              g.ID,
+		Size:            b.GetSize(),
+		BlackHolesCount: b.GetBlackHolesCount(),
+		State:           b.GetState().String(),
+		Moves:           b.GetMoves(),
+		Flags:           b.GetFlagsCount(),
+		ElapsedMs:       b.GetElapsed().Milliseconds(),
+		Board:           rows(snapshot.String()),
+	}
+	// the code gives the layout away, it's for replaying the finished games
+	if c, ok := b.Code(); ok && b.GetState() != model.InProgress {
+		st.Code = c.String()
+	}
+	points := make([]model.Point, 0, len(changed))
+	for p := range changed {
+		points = append(points, p)
+	}
+	model.SortPoints(points)
+	for _, p := range points {
+		st.Changed = append(st.Changed, Cell{X: p.X(), Y: p.Y(), Value: string(snapshot.Get(p.X(), p.Y()).Symbol())})
+	}
+	return st
+}
+
+// rows turns the text format into rows without the spaces between the symbols.
+func rows(text string) []string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(line, " ", "")
+	}
+	return lines
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
@@ -0,0 +1,342 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const layout = `
+	* 1 0 0
+	1 1 0 0
+	1 1 1 1
+	* 1 1 *
+	`
+
+type request struct {
+	method string
+	// path is appended to /games/{id}
+	path string
+	body string
+}
+
+func newTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	s := New(Config{TTL: time.Hour, MaxGames: 10})
+	board, err := model.ParseLayout(layout)
+	if err != nil {
+		t.Fatalf("ParseLayout() error = %v", err)
+	}
+	g, err := s.store.Add(board)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	return s, g.ID
+}
+
+func do(s *Server, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestServer_moves(t *testing.T) {
+	tests := []struct {
+		name        string
+		requests    []request
+		wantStatus  int
+		wantState   string
+		wantBoard   []string
+		wantChanged []Cell
+	}{
+		{
+			name:       "get",
+			requests:   []request{{method: http.MethodGet}},
+			wantStatus: http.StatusOK,
+			wantState:  "in_progress",
+			wantBoard:  []string{"????", "????", "????", "????"},
+		},
+		{
+			name:       "cascade",
+			requests:   []request{{method: http.MethodPost, path: "/open", body: `{"x":2,"y":0}`}},
+			wantStatus: http.StatusOK,
+			wantState:  "in_progress",
+			wantBoard:  []string{"?100", "?100", "?111", "????"},
+			wantChanged: []Cell{
+				{1, 0, "1"}, {2, 0, "0"}, {3, 0, "0"},
+				{1, 1, "1"}, {2, 1, "0"}, {3, 1, "0"},
+				{1, 2, "1"}, {2, 2, "1"}, {3, 2, "1"},
+			},
+		},
+		{
+			name: "flag and chord",
+			requests: []request{
+				{method: http.MethodPost, path: "/open", body: `{"x":2,"y":0}`},
+				{method: http.MethodPost, path: "/flag", body: `{"x":0,"y":0}`},
+				{method: http.MethodPost, path: "/chord", body: `{"x":1,"y":1}`},
+			},
+			wantStatus:  http.StatusOK,
+			wantState:   "in_progress",
+			wantBoard:   []string{"F100", "1100", "1111", "????"},
+			wantChanged: []Cell{{0, 1, "1"}, {0, 2, "1"}},
+		},
+		{
+			name:        "lost",
+			requests:    []request{{method: http.MethodPost, path: "/open", body: `{"x":3,"y":3}`}},
+			wantStatus:  http.StatusOK,
+			wantState:   "lost",
+			wantBoard:   []string{"????", "????", "????", "???*"},
+			wantChanged: []Cell{{3, 3, "*"}},
+		},
+		{
+			name: "move after the end",
+			requests: []request{
+				{method: http.MethodPost, path: "/open", body: `{"x":3,"y":3}`},
+				{method: http.MethodPost, path: "/open", body: `{"x":2,"y":0}`},
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "outside of the board",
+			requests:   []request{{method: http.MethodPost, path: "/open", body: `{"x":4,"y":0}`}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "bad body",
+			requests:   []request{{method: http.MethodPost, path: "/flag", body: `{"x":"a"}`}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown action",
+			requests:   []request{{method: http.MethodPost, path: "/jump", body: `{"x":0,"y":0}`}},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "wrong method",
+			requests:   []request{{method: http.MethodGet, path: "/open"}},
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, id := newTestServer(t)
+			var w *httptest.ResponseRecorder
+			for _, r := range tt.requests {
+				w = do(s, r.method, "/games/"+id+r.path, r.body)
+			}
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var got State
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got.ID != id || got.State != tt.wantState || !reflect.DeepEqual(got.Board, tt.wantBoard) {
+				t.Errorf("state = %+v, want %s %v", got, tt.wantState, tt.wantBoard)
+			}
+			if !reflect.DeepEqual(got.Changed, tt.wantChanged) {
+				t.Errorf("changed = %v, want %v", got.Changed, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestServer_result(t *testing.T) {
+	s, id := newTestServer(t)
+	if w := do(s, http.MethodGet, "/games/"+id+"/result", ""); w.Code != http.StatusConflict {
+		t.Fatalf("in progress result status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	do(s, http.MethodPost, "/games/"+id+"/open", `{"x":0,"y":3}`)
+	w := do(s, http.MethodGet, "/games/"+id+"/result", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("result status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var got Result
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := Result{
+		ID:         id,
+		State:      "lost",
+		Moves:      1,
+		ThreeBV:    got.ThreeBV,
+		TimeMs:     got.TimeMs,
+		LostAt:     &Cell{0, 3, "*"},
+		BlackHoles: []Cell{{0, 0, "*"}, {0, 3, "*"}, {3, 3, "*"}},
+		Layout:     []string{"*100", "1100", "1111", "*11*"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("result = %+v, want %+v", got, want)
+	}
+}
+
+func TestServer_hidesBlackHoles(t *testing.T) {
+	s := New(Config{TTL: time.Hour, MaxGames: 10})
+	w := do(s, http.MethodPost, "/games", `{"size":10,"blackHolesCount":90,"seed":7}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+	var created State
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	// the player knows of closed cells and flags only, anything else on the board or a black hole list leaks the layout
+	for _, w := range []*httptest.ResponseRecorder{
+		do(s, http.MethodPost, "/games/"+created.ID+"/flag", `{"x":0,"y":0}`),
+		do(s, http.MethodGet, "/games/"+created.ID, ""),
+	} {
+		var got State
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if strings.Trim(strings.Join(got.Board, ""), "?F") != "" || strings.Contains(w.Body.String(), `"blackHoles":`) {
+			t.Errorf("state before the end leaks the layout: %s", w.Body)
+		}
+		// the code decodes to the layout
+		if got.Code != "" {
+			t.Errorf("state before the end has the code %s", got.Code)
+		}
+	}
+	if created.Code != "" {
+		t.Errorf("created state has the code %s", created.Code)
+	}
+
+	// any cell ends the game with all but one cell being black holes
+	w = do(s, http.MethodPost, "/games", `{"size":5,"blackHolesCount":24,"seed":7}`)
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	var over State
+	if err := json.Unmarshal(do(s, http.MethodPost, "/games/"+created.ID+"/open", `{"x":2,"y":2}`).Body.Bytes(), &over); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if over.State == "in_progress" || over.Code == "" {
+		t.Errorf("finished state = %s with code %q, want the code", over.State, over.Code)
+	}
+}
+
+func TestServer_create(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantSize   int
+		wantHoles  int
+	}{
+		{name: "default", body: `{}`, wantStatus: http.StatusCreated, wantSize: model.Easy.Size, wantHoles: model.Easy.BlackHolesCount},
+		{name: "difficulty", body: `{"difficulty":"medium"}`, wantStatus: http.StatusCreated, wantSize: model.Medium.Size, wantHoles: model.Medium.BlackHolesCount},
+		{name: "custom", body: `{"size":12,"blackHolesCount":20,"seed":3}`, wantStatus: http.StatusCreated, wantSize: 12, wantHoles: 20},
+		{name: "code", body: `{"code":"` + model.DailyCode(time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)).String() + `"}`, wantStatus: http.StatusCreated, wantSize: model.Daily.Size, wantHoles: model.Daily.BlackHolesCount},
+		{name: "unknown difficulty", body: `{"difficulty":"insane"}`, wantStatus: http.StatusBadRequest},
+		{name: "too many black holes", body: `{"size":5,"blackHolesCount":26}`, wantStatus: http.StatusBadRequest},
+		{name: "bad code", body: `{"code":"nope"}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown field", body: `{"mines":3}`, wantStatus: http.StatusBadRequest},
+		{name: "unsupported generator", body: `{"generator":9}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(Config{TTL: time.Hour, MaxGames: 10})
+			w := do(s, http.MethodPost, "/games", tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+			var got State
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got.Size != tt.wantSize || got.BlackHolesCount != tt.wantHoles || got.ID == "" {
+				t.Errorf("state = %+v, want size %d and %d black holes", got, tt.wantSize, tt.wantHoles)
+			}
+			if w := do(s, http.MethodGet, "/games/"+got.ID, ""); w.Code != http.StatusOK {
+				t.Errorf("get status = %d, want %d", w.Code, http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestServer_lifecycle(t *testing.T) {
+	s := New(Config{TTL: time.Minute, MaxGames: 1})
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.store.now = func() time.Time { return now }
+
+	var created State
+	w := do(s, http.MethodPost, "/games", `{}`)
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if w := do(s, http.MethodPost, "/games", `{}`); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("create on a full store status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	now = now.Add(50 * time.Second)
+	if w := do(s, http.MethodGet, "/games/"+created.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("get before expiry status = %d, want %d", w.Code, http.StatusOK)
+	}
+	now = now.Add(50 * time.Second)
+	if w := do(s, http.MethodGet, "/games/"+created.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("get of a game used in the ttl status = %d, want %d", w.Code, http.StatusOK)
+	}
+	now = now.Add(2 * time.Minute)
+	if w := do(s, http.MethodGet, "/games/"+created.ID, ""); w.Code != http.StatusNotFound {
+		t.Errorf("get after expiry status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := do(s, http.MethodPost, "/games", `{}`); w.Code != http.StatusCreated {
+		t.Errorf("create after expiry status = %d, want %d", w.Code, http.StatusCreated)
+	}
+
+	if w := do(s, http.MethodPost, "/games", `{}`); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("create on a full store status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	for _, id := range idsOf(s) {
+		if w := do(s, http.MethodDelete, "/games/"+id, ""); w.Code != http.StatusNoContent {
+			t.Errorf("delete status = %d, want %d", w.Code, http.StatusNoContent)
+		}
+	}
+	if s.store.Len() != 0 {
+		t.Errorf("Len() after delete = %d, want 0", s.store.Len())
+	}
+}
+
+func idsOf(s *Server) []string {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	var ids []string
+	for id := range s.store.games {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestServer_concurrentMoves(t *testing.T) {
+	s, id := newTestServer(t)
+	done := make(chan int)
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			go func(x, y int) {
+				body, _ := json.Marshal(MoveRequest{X: x, Y: y})
+				done <- do(s, http.MethodPost, "/games/"+id+"/flag", string(body)).Code
+			}(x, y)
+		}
+	}
+	for i := 0; i < 16; i++ {
+		if code := <-done; code != http.StatusOK {
+			t.Errorf("flag status = %d, want %d", code, http.StatusOK)
+		}
+	}
+	var got State
+	json.Unmarshal(do(s, http.MethodGet, "/games/"+id, "").Body.Bytes(), &got)
+	if got.Flags != 16 {
+		t.Errorf("flags = %d, want 16", got.Flags)
+	}
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"sync"
+	"time"
+)
+
+// ErrFull is returned when the store keeps as many games as it can.
+var ErrFull = errors.New("too many games, try again later")
+
+// Game is a game of the store, its mutex guards the board and the live clients.
+type Game struct {
+	ID string
+	mu sync.Mutex
+	// board is only read and changed with mu held
+	board   model.Board
+	clients map[*client]bool
+	// usedAt is guarded by the store mutex
+	usedAt time.Time
+}
+
+func newGame(id string, board model.Board) *Game {
+	g := &Game{ID: id, board: board, clients: map[*client]bool{}}
+	g.board.Subscribe(g.broadcast)
+	return g
+}
+
+// Store keeps the games in memory, the games not used for the ttl expire.
+type Store struct {
+	mu    sync.Mutex
+	games map[string]*Game
+	ttl   time.Duration
+	max   int
+	now   func() time.Time
+}
+
+func NewStore(ttl time.Duration, max int) *Store {
+	return &Store{games: map[string]*Game{}, ttl: ttl, max: max, now: time.Now}
+}
+
+// Add stores the board as a new game with a random id.
+func (s *Store) Add(board model.Board) (*Game, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	if len(s.games) >= s.max {
+		return nil, ErrFull
+	}
+	g := newGame(id, board)
+	g.usedAt = s.now()
+	s.games[id] = g
+	return g, nil
+}
+
+// Get returns the game and keeps it from expiring for another ttl, ok is false for unknown or expired games.
+func (s *Store) Get(id string) (g *Game, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok = s.games[id]
+	if !ok {
+		return nil, false
+	}
+	if s.expired(g) {
+		s.remove(g)
+		return nil, false
+	}
+	g.usedAt = s.now()
+	return g, true
+}
+
+// Delete forgets the game and disconnects its live clients.
+func (s *Store) Delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.games[id]; ok {
+		s.remove(g)
+	}
+}
+
+// Len returns the number of games kept, expired ones included until they are swept.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.games)
+}
+
+func (s *Store) expire() {
+	for _, g := range s.games {
+		if s.expired(g) {
+			s.remove(g)
+		}
+	}
+}
+
+func (s *Store) remove(g *Game) {
+	delete(s.games, g.ID)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for c := range g.clients {
+		g.disconnect(c)
+	}
+}
+
+func (s *Store) expired(g *Game) bool {
+	return s.now().Sub(g.usedAt) > s.ttl
+}
+
+// newID returns an id nobody can guess, so games can't be played by others.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,144 @@
+package sim
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type Durations struct {
+	Min  time.Duration `json:"min"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+type Report struct {
+	Strategy        string  `json:"strategy"`
+	Size            int     `json:"size"`
+	BlackHolesCount int     `json:"blackHolesCount"`
+	Seed            int64   `json:"seed"`
+	Games           int     `json:"games"`
+	Wins            int     `json:"wins"`
+	WinRate         float64 `json:"winRate"`
+	AverageMoves    float64 `json:"averageMoves"`
+	TotalGuesses    int     `json:"totalGuesses"`
+	AverageGuesses  float64 `json:"averageGuesses"`
+	NoGuessGames    int     `json:"noGuessGames"`
+	AverageThreeBV  float64 `json:"average3bv"`
+	// AverageRequiredGuesses is how many guesses the model solver needs on the same boards.
+	AverageRequiredGuesses float64   `json:"averageRequiredGuesses"`
+	Durations              Durations `json:"durationsNs"`
+}
+
+func NewReport(cfg Config, results []GameResult) Report {
+	r := Report{
+		Strategy:        cfg.Strategy,
+		Size:            cfg.Size,
+		BlackHolesCount: cfg.BlackHolesCount,
+		Seed:            cfg.Seed,
+		Games:           len(results),
+	}
+	if len(results) == 0 {
+		return r
+	}
+	moves, threeBV, requiredGuesses := 0, 0, 0
+	durations := make([]time.Duration, 0, len(results))
+	var total time.Duration
+	for _, res := range results {
+		if res.Won {
+			r.Wins++
+		}
+		if res.Guesses <= 1 {
+			r.NoGuessGames++
+		}
+		moves += res.Moves
+		threeBV += res.Metrics.ThreeBV
+		requiredGuesses += res.Metrics.Guesses
+		r.TotalGuesses += res.Guesses
+		durations = append(durations, res.Duration)
+		total += res.Duration
+	}
+	n := float64(len(results))
+	r.WinRate = float64(r.Wins) / n
+	r.AverageMoves = float64(moves) / n
+	r.AverageGuesses = float64(r.TotalGuesses) / n
+	r.AverageThreeBV = float64(threeBV) / n
+	r.AverageRequiredGuesses = float64(requiredGuesses) / n
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	r.Durations = Durations{
+		Min:  durations[0],
+		Mean: total / time.Duration(len(durations)),
+		P50:  percentile(durations, 50),
+		P90:  percentile(durations, 90),
+		P99:  percentile(durations, 99),
+		Max:  durations[len(durations)-1],
+	}
+	return r
+}
+
+func percentile(sorted []time.Duration, p int) time.Duration {
+	i := (len(sorted)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+func (r Report) WriteText(w io.Writer) error {
+	d := r.Durations
+	_, err := fmt.Fprintf(w, `strategy:        %s
+board:           %dx%d, %d black holes
+seed:            %d
+games:           %d
+wins:            %d (%.2f%%)
+average moves:   %.2f
+guesses:         %d total, %.2f per game, %d games with no guess after the first move
+required:        %.2f guesses per game by the solver
+average 3BV:     %.2f
+game duration:   min %v, mean %v, p50 %v, p90 %v, p99 %v, max %v
+`,
+		r.Strategy, r.Size, r.Size, r.BlackHolesCount, r.Seed, r.Games, r.Wins, r.WinRate*100, r.AverageMoves,
+		r.TotalGuesses, r.AverageGuesses, r.NoGuessGames, r.AverageRequiredGuesses, r.AverageThreeBV, d.Min, d.Mean, d.P50, d.P90, d.P99, d.Max)
+	return err
+}
+
+func (r Report) WriteJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(r)
+}
+
+func (r Report) WriteCSV(w io.Writer) error {
+	d := r.Durations
+	cw := csv.NewWriter(w)
+	records := [][]string{
+		{"strategy", "size", "black_holes", "seed", "games", "wins", "win_rate", "average_moves", "total_guesses",
+			"average_guesses", "no_guess_games", "average_required_guesses", "average_3bv", "min_ns", "mean_ns", "p50_ns", "p90_ns", "p99_ns", "max_ns"},
+		{r.Strategy, strconv.Itoa(r.Size), strconv.Itoa(r.BlackHolesCount), strconv.FormatInt(r.Seed, 10),
+			strconv.Itoa(r.Games), strconv.Itoa(r.Wins), formatFloat(r.WinRate), formatFloat(r.AverageMoves),
+			strconv.Itoa(r.TotalGuesses), formatFloat(r.AverageGuesses), strconv.Itoa(r.NoGuessGames),
+			formatFloat(r.AverageRequiredGuesses), formatFloat(r.AverageThreeBV),
+			formatDuration(d.Min), formatDuration(d.Mean), formatDuration(d.P50), formatDuration(d.P90),
+			formatDuration(d.P99), formatDuration(d.Max)},
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
+
+func formatDuration(d time.Duration) string {
+	return strconv.FormatInt(d.Nanoseconds(), 10)
+}
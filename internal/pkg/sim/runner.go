@@ -0,0 +1,98 @@
+package sim
+
+import (
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	Size            int
+	BlackHolesCount int
+	Games           int
+	Seed            int64
+	Workers         int
+	Strategy        string
+}
+
+type GameResult struct {
+	Seed     int64
+	Won      bool
+	Moves    int
+	Guesses  int
+	Duration time.Duration
+	Metrics  model.Metrics
+}
+
+// Run plays cfg.Games games in parallel. Results are stored by game index,
+// so the same config always produces the same results regardless of scheduling.
+func Run(cfg Config) ([]GameResult, error) {
+	if cfg.Games <= 0 {
+		return nil, fmt.Errorf("games should be greater then 0")
+	}
+	factory, err := lookupStrategy(cfg.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	// validate board parameters once instead of failing in every worker
+	if _, err := model.NewBoard(model.SeededCoordinatesProvider{Seed: cfg.Seed}, cfg.Size, cfg.BlackHolesCount); err != nil {
+		return nil, err
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	results := make([]GameResult, cfg.Games)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = play(cfg, factory, DeriveSeed(cfg.Seed, i))
+			}
+		}()
+	}
+	for i := 0; i < cfg.Games; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results, nil
+}
+
+func play(cfg Config, factory Factory, seed int64) GameResult {
+	board, _ := model.NewBoard(model.SeededCoordinatesProvider{Seed: seed}, cfg.Size, cfg.BlackHolesCount)
+	res := GameResult{Seed: seed, Metrics: board.Metrics()}
+
+	start := time.Now()
+	strategy := factory(rand.New(rand.NewSource(DeriveSeed(seed, 0))))
+	view := View{board: &board}
+	// every sensible move opens at least one cell, the limit only protects from stuck strategies
+	for board.GetState() == model.InProgress && res.Moves < cfg.Size*cfg.Size {
+		m := strategy.Next(view)
+		if m.Guess {
+			res.Guesses++
+		}
+		board.Open(m.X, m.Y)
+		res.Moves++
+	}
+	res.Won = board.GetState() == model.Won
+	res.Duration = time.Since(start)
+	return res
+}
+
+// DeriveSeed mixes the master seed with the game index (splitmix64 finalizer),
+// so neighbouring games get unrelated boards.
+func DeriveSeed(master int64, i int) int64 {
+	z := uint64(master) + uint64(i+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
@@ -0,0 +1,106 @@
+package sim
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRun_Reproducible(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{
+			name: "simple strategy on easy board",
+			cfg:  Config{Size: 8, BlackHolesCount: 10, Games: 50, Seed: 42, Workers: 4, Strategy: "simple"},
+		},
+		{
+			name: "random strategy on custom board",
+			cfg:  Config{Size: 5, BlackHolesCount: 3, Games: 50, Seed: 7, Workers: 3, Strategy: "random"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, err := Run(tt.cfg)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			single := tt.cfg
+			single.Workers = 1
+			second, err := Run(single)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			for i := range first {
+				a, b := first[i], second[i]
+				a.Duration, b.Duration = 0, 0
+				if a != b {
+					t.Errorf("game %d: %+v != %+v", i, a, b)
+				}
+				if a.Moves == 0 || a.Guesses == 0 {
+					t.Errorf("game %d: expected at least one move and the first guess, got %+v", i, a)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		errorMessage string
+	}{
+		{
+			name:         "no games",
+			cfg:          Config{Size: 8, BlackHolesCount: 10, Strategy: "simple"},
+			errorMessage: "games should be greater then 0",
+		},
+		{
+			name:         "unknown strategy",
+			cfg:          Config{Size: 8, BlackHolesCount: 10, Games: 1, Strategy: "psychic"},
+			errorMessage: `unknown strategy "psychic", available: [random simple]`,
+		},
+		{
+			name:         "invalid board",
+			cfg:          Config{Size: 3, BlackHolesCount: 10, Games: 1, Strategy: "simple"},
+			errorMessage: "blackHoleCount should be less then or equal to board square (size*size)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Run(tt.cfg)
+			if err == nil || err.Error() != tt.errorMessage {
+				t.Errorf("Run() error = %v, want %v", err, tt.errorMessage)
+			}
+		})
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	results := []GameResult{
+		{Won: true, Moves: 10, Guesses: 1, Duration: 4 * time.Millisecond},
+		{Won: false, Moves: 4, Guesses: 3, Duration: 1 * time.Millisecond},
+		{Won: true, Moves: 7, Guesses: 2, Duration: 2 * time.Millisecond},
+		{Won: false, Moves: 3, Guesses: 2, Duration: 3 * time.Millisecond},
+	}
+	r := NewReport(Config{Strategy: "simple"}, results)
+
+	if r.Games != 4 || r.Wins != 2 || r.WinRate != 0.5 {
+		t.Errorf("games/wins = %d/%d (%v), want 4/2 (0.5)", r.Games, r.Wins, r.WinRate)
+	}
+	if r.AverageMoves != 6 || r.TotalGuesses != 8 || r.AverageGuesses != 2 || r.NoGuessGames != 1 {
+		t.Errorf("unexpected moves/guesses: %+v", r)
+	}
+	want := Durations{
+		Min:  time.Millisecond,
+		Mean: 2500 * time.Microsecond,
+		P50:  2 * time.Millisecond,
+		P90:  4 * time.Millisecond,
+		P99:  4 * time.Millisecond,
+		Max:  4 * time.Millisecond,
+	}
+	if r.Durations != want {
+		t.Errorf("durations = %+v, want %+v", r.Durations, want)
+	}
+}
@@ -0,0 +1,155 @@
+package sim
+
+import (
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"math/rand"
+	"sort"
+)
+
+// View exposes only what a player could see on the screen, so strategies
+// can't peek at the black holes.
+type View struct {
+	board *model.Board
+}
+
+func (v View) Size() int {
+	return v.board.GetSize()
+}
+
+func (v View) IsOpened(x, y int) bool {
+	return v.board.IsOpened(x, y)
+}
+
+// NeighboursCount returns the number shown in an opened cell; ok is false for closed cells.
+func (v View) NeighboursCount(x, y int) (count int, ok bool) {
+	if !v.board.IsOpened(x, y) {
+		return 0, false
+	}
+	return v.board.GetNeighboursCount(x, y), true
+}
+
+type Move struct {
+	X     int
+	Y     int
+	Guess bool
+}
+
+type Strategy interface {
+	Next(v View) Move
+}
+
+// Factory creates a fresh strategy for every game, rnd is seeded from the game seed.
+type Factory func(rnd *rand.Rand) Strategy
+
+var strategies = map[string]Factory{
+	"random": newRandomStrategy,
+	"simple": newSimpleStrategy,
+}
+
+func Register(name string, f Factory) {
+	strategies[name] = f
+}
+
+func StrategyNames() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func lookupStrategy(name string) (Factory, error) {
+	f, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q, available: %v", name, StrategyNames())
+	}
+	return f, nil
+}
+
+type randomStrategy struct {
+	rnd *rand.Rand
+}
+
+func newRandomStrategy(rnd *rand.Rand) Strategy {
+	return &randomStrategy{rnd: rnd}
+}
+
+func (s *randomStrategy) Next(v View) Move {
+	closed := closedCells(v, nil)
+	p := closed[s.rnd.Intn(len(closed))]
+	return Move{X: p[0], Y: p[1], Guess: true}
+}
+
+// simpleStrategy applies the two basic single cell rules: a number surrounded by exactly
+// that many closed cells marks them all as black holes, a number already satisfied by
+// known black holes makes the rest of its closed neighbours safe. It guesses otherwise.
+type simpleStrategy struct {
+	rnd        *rand.Rand
+	blackHoles map[[2]int]bool
+}
+
+func newSimpleStrategy(rnd *rand.Rand) Strategy {
+	return &simpleStrategy{rnd: rnd, blackHoles: map[[2]int]bool{}}
+}
+
+func (s *simpleStrategy) Next(v View) Move {
+	for changed := true; changed; {
+		changed = false
+		for y := 0; y < v.Size(); y++ {
+			for x := 0; x < v.Size(); x++ {
+				count, ok := v.NeighboursCount(x, y)
+				if !ok || count == 0 {
+					continue
+				}
+				closed, known := s.closedNeighbours(v, x, y)
+				if len(closed) == 0 {
+					continue
+				}
+				if count == known {
+					return Move{X: closed[0][0], Y: closed[0][1]}
+				}
+				if count == known+len(closed) {
+					for _, p := range closed {
+						s.blackHoles[p] = true
+					}
+					changed = true
+				}
+			}
+		}
+	}
+	candidates := closedCells(v, s.blackHoles)
+	p := candidates[s.rnd.Intn(len(candidates))]
+	return Move{X: p[0], Y: p[1], Guess: true}
+}
+
+// closedNeighbours returns closed neighbours not known to be black holes and the number of known black holes around.
+func (s *simpleStrategy) closedNeighbours(v View, x, y int) (closed [][2]int, known int) {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			nx, ny := x+dx, y+dy
+			if (dx == 0 && dy == 0) || nx < 0 || ny < 0 || nx >= v.Size() || ny >= v.Size() || v.IsOpened(nx, ny) {
+				continue
+			}
+			if s.blackHoles[[2]int{nx, ny}] {
+				known++
+				continue
+			}
+			closed = append(closed, [2]int{nx, ny})
+		}
+	}
+	return closed, known
+}
+
+func closedCells(v View, exclude map[[2]int]bool) [][2]int {
+	var closed [][2]int
+	for y := 0; y < v.Size(); y++ {
+		for x := 0; x < v.Size(); x++ {
+			if !v.IsOpened(x, y) && !exclude[[2]int{x, y}] {
+				closed = append(closed, [2]int{x, y})
+			}
+		}
+	}
+	return closed
+}
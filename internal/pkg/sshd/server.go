@@ -0,0 +1,248 @@
+package sshd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/config"
+	"golang.org/x/crypto/ssh"
+	"io/fs"
+	"log"
+	"net"
+	"os"
+	"strings"
+)
+
+// HostKeyFileName is the host key in the user config directory.
+const HostKeyFileName = "ssh_host_ed25519_key"
+
+// playerExtension carries the player of the authorized key from the authentication to the session.
+const playerExtension = "player"
+
+// Play runs the game of the player on the screen of the session, the session ends when it returns.
+// term is the terminal type of the client.
+type Play func(player, term string, s tcell.Screen) error
+
+// AuthorizedKey is a key allowed to log in. Its player is the comment of the key, whatever the SSH user name.
+type AuthorizedKey struct {
+	Key    ssh.PublicKey
+	Player string
+}
+
+type Config struct {
+	HostKey ssh.Signer
+	// AuthorizedKeys are the keys allowed to log in, anyone can play under the SSH user name if empty.
+	AuthorizedKeys []AuthorizedKey
+	Play           Play
+}
+
+// Server runs a game for every SSH session with a pty.
+type Server struct {
+	config *ssh.ServerConfig
+	play   Play
+}
+
+func NewServer(cfg Config) *Server {
+	sc := &ssh.ServerConfig{NoClientAuth: len(cfg.AuthorizedKeys) == 0}
+	if len(cfg.AuthorizedKeys) > 0 {
+		sc.PublicKeyCallback = func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			for _, k := range cfg.AuthorizedKeys {
+				if bytes.Equal(k.Key.Marshal(), key.Marshal()) {
+					return &ssh.Permissions{Extensions: map[string]string{playerExtension: k.Player}}, nil
+				}
+			}
+			return nil, fmt.Errorf("unknown key for %s", conn.User())
+		}
+	}
+	sc.AddHostKey(cfg.HostKey)
+	return &Server{config: sc, play: cfg.Play}
+}
+
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts the connections until the listener fails.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	sc, channels, requests, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		log.Printf("ssh handshake with %s failed: %v", conn.RemoteAddr(), err)
+		return
+	}
+	defer sc.Close()
+	go ssh.DiscardRequests(requests)
+	// anyone can pick the SSH user name, the authorized key tells who plays
+	player := sc.User()
+	if sc.Permissions != nil && sc.Permissions.Extensions[playerExtension] != "" {
+		player = sc.Permissions.Extensions[playerExtension]
+	}
+	for nc := range channels {
+		if nc.ChannelType() != "session" {
+			nc.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		ch, requests, err := nc.Accept()
+		if err != nil {
+			continue
+		}
+		go s.session(player, ch, requests)
+	}
+}
+
+// ptyRequest and windowChange are the payloads of RFC 4254, the modes are ignored.
+type ptyRequest struct {
+	Term        string
+	Width       uint32
+	Height      uint32
+	PixelWidth  uint32
+	PixelHeight uint32
+	Modes       string
+}
+
+type windowChange struct {
+	Width       uint32
+	Height      uint32
+	PixelWidth  uint32
+	PixelHeight uint32
+}
+
+// session starts the game on the shell request and finalizes its screen when the client goes away.
+func (s *Server) session(player string, ch ssh.Channel, requests <-chan *ssh.Request) {
+	defer ch.Close()
+	// the client may still send requests until it gets the close
+	defer func() { go ssh.DiscardRequests(requests) }()
+	t := newTTY(ch)
+	term := ""
+	var screen tcell.Screen
+	done := make(chan error, 1)
+	for {
+		select {
+		case req, ok := <-requests:
+			if !ok {
+				if screen != nil {
+					screen.Fini()
+					<-done
+				}
+				return
+			}
+			switch req.Type {
+			case "pty-req":
+				var p ptyRequest
+				if err := ssh.Unmarshal(req.Payload, &p); err != nil {
+					req.Reply(false, nil)
+					continue
+				}
+				term = p.Term
+				t.setSize(int(p.Width), int(p.Height))
+				req.Reply(true, nil)
+			case "window-change":
+				var w windowChange
+				if err := ssh.Unmarshal(req.Payload, &w); err == nil {
+					t.setSize(int(w.Width), int(w.Height))
+				}
+				req.Reply(true, nil)
+			case "shell":
+				if screen != nil {
+					req.Reply(false, nil)
+					continue
+				}
+				var err error
+				if screen, err = newScreen(t, term); err != nil {
+					req.Reply(false, nil)
+					exit(ch, err)
+					return
+				}
+				req.Reply(true, nil)
+				go func() { done <- s.play(player, term, screen) }()
+			default:
+				req.Reply(false, nil)
+			}
+		case err := <-done:
+			exit(ch, err)
+			return
+		}
+	}
+}
+
+func newScreen(t *tty, term string) (tcell.Screen, error) {
+	if term == "" {
+		return nil, errors.New("the game needs a terminal, connect with ssh -t")
+	}
+	ti, err := tcell.LookupTerminfo(term)
+	if err != nil {
+		return nil, fmt.Errorf("terminal %s isn't supported: %w", term, err)
+	}
+	return tcell.NewTerminfoScreenFromTtyTerminfo(t, ti)
+}
+
+// exit sends the error to the client and the exit status.
+func exit(ch ssh.Channel, err error) {
+	status := struct{ Status uint32 }{}
+	if err != nil {
+		fmt.Fprintf(ch.Stderr(), "%v\r\n", err)
+		status.Status = 1
+	}
+	ch.SendRequest("exit-status", false, ssh.Marshal(status))
+}
+
+// LoadHostKey reads the private key at the path, a new ed25519 key is generated and written there if there is none.
+func LoadHostKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return ssh.ParsePrivateKey(data)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(key, "galaxy_tramp host key")
+	if err != nil {
+		return nil, err
+	}
+	if err := config.WriteFileAtomic(path, pem.EncodeToMemory(block)); err != nil {
+		return nil, err
+	}
+	return ssh.NewSignerFromKey(key)
+}
+
+// ParseAuthorizedKeys reads the keys of an authorized_keys file, the comment of a key names its player
+// and the options are ignored.
+func ParseAuthorizedKeys(data []byte) ([]AuthorizedKey, error) {
+	var keys []AuthorizedKey
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if comment == "" {
+			return nil, fmt.Errorf("line %d: the key has no comment naming its player", i+1)
+		}
+		keys = append(keys, AuthorizedKey{Key: key, Player: comment})
+	}
+	return keys, nil
+}
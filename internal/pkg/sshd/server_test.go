@@ -0,0 +1,276 @@
+package sshd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"github.com/gdamore/tcell/v2"
+	"golang.org/x/crypto/ssh"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// echoPlay shows the user and the window size, and returns on a key.
+func echoPlay(user, term string, s tcell.Screen) error {
+	if err := s.Init(); err != nil {
+		return err
+	}
+	defer s.Fini()
+	w, h := s.Size()
+	for i, r := range user + " " + term + " " + strings.Repeat("#", w/10) + strings.Repeat("|", h/10) {
+		s.SetContent(i, 0, r, nil, tcell.StyleDefault)
+	}
+	s.Show()
+	for {
+		switch e := s.PollEvent().(type) {
+		case nil:
+			return errors.New("closed")
+		case *tcell.EventKey:
+			if e.Rune() == 'x' {
+				return errors.New("game over")
+			}
+			return nil
+		}
+	}
+}
+
+func newTestServer(t *testing.T, authorized []AuthorizedKey) string {
+	t.Helper()
+	hostKey, err := LoadHostKey(filepath.Join(t.TempDir(), HostKeyFileName))
+	if err != nil {
+		t.Fatalf("LoadHostKey() error = %v", err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go NewServer(Config{HostKey: hostKey, AuthorizedKeys: authorized, Play: echoPlay}).Serve(l)
+	return l.Addr().String()
+}
+
+func dial(addr, user string, auth ...ssh.AuthMethod) (*ssh.Client, error) {
+	return ssh.Dial("tcp", addr, &ssh.ClientConfig{
+		User:            user,
+		Auth:            auth,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+}
+
+// screenWriter collects the output and signals when it contains the wanted text.
+type screenWriter struct {
+	mu    sync.Mutex
+	out   bytes.Buffer
+	want  string
+	found chan struct{}
+}
+
+func (w *screenWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n, err := w.out.Write(p)
+	if w.want != "" && strings.Contains(w.out.String(), w.want) {
+		w.want = ""
+		close(w.found)
+	}
+	return n, err
+}
+
+func (w *screenWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.out.String()
+}
+
+func TestServer_session(t *testing.T) {
+	tests := []struct {
+		name       string
+		pty        bool
+		key        string
+		wantScreen string
+		wantStatus int
+	}{
+		{name: "quit", pty: true, key: "q", wantScreen: "tramp xterm ########||", wantStatus: 0},
+		{name: "game error", pty: true, key: "x", wantScreen: "tramp xterm ########||", wantStatus: 1},
+		{name: "no pty", wantStatus: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := dial(newTestServer(t, nil), "tramp")
+			if err != nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			defer client.Close()
+			session, err := client.NewSession()
+			if err != nil {
+				t.Fatalf("NewSession() error = %v", err)
+			}
+			defer session.Close()
+			if tt.pty {
+				if err := session.RequestPty("xterm", 24, 80, ssh.TerminalModes{}); err != nil {
+					t.Fatalf("RequestPty() error = %v", err)
+				}
+			}
+			out := &screenWriter{want: tt.wantScreen, found: make(chan struct{})}
+			session.Stdout = out
+			in, err := session.StdinPipe()
+			if err != nil {
+				t.Fatalf("StdinPipe() error = %v", err)
+			}
+			err = session.Shell()
+			if tt.pty {
+				if err != nil {
+					t.Fatalf("Shell() error = %v", err)
+				}
+				select {
+				case <-out.found:
+				case <-time.After(5 * time.Second):
+					t.Fatalf("screen = %q, want %q", out.String(), tt.wantScreen)
+				}
+				in.Write([]byte(tt.key))
+			}
+			err = session.Wait()
+			status := 0
+			var exitErr *ssh.ExitError
+			if errors.As(err, &exitErr) {
+				status = exitErr.ExitStatus()
+			}
+			if tt.pty && status != tt.wantStatus {
+				t.Errorf("exit status = %d (%v), want %d", status, err, tt.wantStatus)
+			}
+			if !tt.pty && err == nil {
+				t.Errorf("session without a pty didn't fail")
+			}
+		})
+	}
+}
+
+func newSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("NewSignerFromKey() error = %v", err)
+	}
+	return signer
+}
+
+// authorizedKey returns the authorized_keys line of the signer with the comment.
+func authorizedKey(signer ssh.Signer, comment string) string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))) + " " + comment + "\n"
+}
+
+func TestServer_authorizedKeys(t *testing.T) {
+	alice, bob, other := newSigner(t), newSigner(t), newSigner(t)
+	keys, err := ParseAuthorizedKeys([]byte("# team\n\n" + authorizedKey(alice, "alice") + authorizedKey(bob, "bob")))
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("ParseAuthorizedKeys() = %v, %v, want two keys", keys, err)
+	}
+	addr := newTestServer(t, keys)
+
+	tests := []struct {
+		name       string
+		user       string
+		auth       []ssh.AuthMethod
+		wantScreen string
+		wantErr    bool
+	}{
+		{name: "authorized key", user: "alice", auth: []ssh.AuthMethod{ssh.PublicKeys(alice)}, wantScreen: "alice xterm"},
+		// the SSH user name doesn't pick the player, bob can't play as alice
+		{name: "another user name", user: "alice", auth: []ssh.AuthMethod{ssh.PublicKeys(bob)}, wantScreen: "bob xterm"},
+		{name: "other key", user: "alice", auth: []ssh.AuthMethod{ssh.PublicKeys(other)}, wantErr: true},
+		{name: "no key", user: "alice", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := dial(addr, tt.user, tt.auth...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Dial() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer client.Close()
+			session, err := client.NewSession()
+			if err != nil {
+				t.Fatalf("NewSession() error = %v", err)
+			}
+			defer session.Close()
+			if err := session.RequestPty("xterm", 24, 80, ssh.TerminalModes{}); err != nil {
+				t.Fatalf("RequestPty() error = %v", err)
+			}
+			out := &screenWriter{want: tt.wantScreen, found: make(chan struct{})}
+			session.Stdout = out
+			in, err := session.StdinPipe()
+			if err != nil {
+				t.Fatalf("StdinPipe() error = %v", err)
+			}
+			if err := session.Shell(); err != nil {
+				t.Fatalf("Shell() error = %v", err)
+			}
+			select {
+			case <-out.found:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("screen = %q, want %q", out.String(), tt.wantScreen)
+			}
+			in.Write([]byte("q"))
+			session.Wait()
+		})
+	}
+}
+
+func TestLoadHostKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), HostKeyFileName)
+	generated, err := LoadHostKey(path)
+	if err != nil {
+		t.Fatalf("LoadHostKey() error = %v", err)
+	}
+	loaded, err := LoadHostKey(path)
+	if err != nil {
+		t.Fatalf("LoadHostKey() of the generated key error = %v", err)
+	}
+	if !bytes.Equal(generated.PublicKey().Marshal(), loaded.PublicKey().Marshal()) {
+		t.Errorf("LoadHostKey() returned another key than the generated one")
+	}
+}
+
+func TestParseAuthorizedKeys(t *testing.T) {
+	signer := newSigner(t)
+	tests := []struct {
+		name        string
+		data        string
+		wantPlayers []string
+		wantErr     bool
+	}{
+		{name: "keys", data: "# team\n\n" + authorizedKey(signer, "alice") + authorizedKey(signer, "bob@laptop"), wantPlayers: []string{"alice", "bob@laptop"}},
+		{name: "options", data: `no-pty,from="10.0.0.*" ` + authorizedKey(signer, "alice"), wantPlayers: []string{"alice"}},
+		{name: "empty", data: "\n# nobody yet\n"},
+		{name: "no comment", data: authorizedKey(signer, ""), wantErr: true},
+		{name: "bad key", data: "ssh-ed25519 nonsense\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAuthorizedKeys([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAuthorizedKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.wantPlayers) {
+				t.Fatalf("ParseAuthorizedKeys() = %d keys, want %d", len(got), len(tt.wantPlayers))
+			}
+			for i, k := range got {
+				if k.Player != tt.wantPlayers[i] || !bytes.Equal(k.Key.Marshal(), signer.PublicKey().Marshal()) {
+					t.Errorf("key %d player = %q, want %q", i, k.Player, tt.wantPlayers[i])
+				}
+			}
+		})
+	}
+}
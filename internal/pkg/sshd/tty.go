@@ -0,0 +1,90 @@
+package sshd
+
+import (
+	"errors"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"sync"
+)
+
+var errDrained = errors.New("tty drained")
+
+// defaultWidth and defaultHeight are the size of the clients not telling theirs.
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// tty is the terminal of an SSH session for tcell. The input goes through a pipe, so Drain can wake
+// a pending Read up when the screen is finalized.
+type tty struct {
+	ch     ssh.Channel
+	in     *io.PipeReader
+	mu     sync.Mutex
+	width  int
+	height int
+	resize func()
+}
+
+func newTTY(ch ssh.Channel) *tty {
+	r, w := io.Pipe()
+	go func() {
+		_, err := io.Copy(w, ch)
+		if err == nil {
+			err = io.EOF
+		}
+		w.CloseWithError(err)
+	}()
+	return &tty{ch: ch, in: r, width: defaultWidth, height: defaultHeight}
+}
+
+func (t *tty) Read(p []byte) (int, error) {
+	return t.in.Read(p)
+}
+
+func (t *tty) Write(p []byte) (int, error) {
+	return t.ch.Write(p)
+}
+
+// Close leaves the channel to the session, it sends the exit status before closing it.
+func (t *tty) Close() error {
+	return nil
+}
+
+// Start has nothing to do, the client puts its terminal in raw mode for the pty.
+func (t *tty) Start() error {
+	return nil
+}
+
+func (t *tty) Stop() error {
+	return nil
+}
+
+func (t *tty) Drain() error {
+	return t.in.CloseWithError(errDrained)
+}
+
+func (t *tty) NotifyResize(cb func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.resize = cb
+}
+
+func (t *tty) WindowSize() (width int, height int, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.width, t.height, nil
+}
+
+func (t *tty) setSize(width, height int) {
+	if width == 0 || height == 0 {
+		return
+	}
+	t.mu.Lock()
+	t.width, t.height = width, height
+	resize := t.resize
+	t.mu.Unlock()
+	if resize != nil {
+		resize()
+	}
+}
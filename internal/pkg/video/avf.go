@@ -0,0 +1,151 @@
+package video
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"io"
+	"strings"
+	"time"
+)
+
+// An AVF video starts with a version byte, 4 bytes not used here and the level: 3 beginner, 4 intermediate,
+// 5 expert or 6 custom followed by the width-1, the height-1 and the number of mines as a big endian uint16.
+// The row+1 and the column+1 of every mine follow, then the start and the end times as [start|end] and
+// the mouse events of 8 bytes: the event type, then the bytes of x, y and the time interleaved as
+// x1 s0 x0 c y1 s1 y0, where s is the second+1 and c the hundredths. The events end at the first byte
+// that isn't an event type.
+const (
+	avfBeginner     = 3
+	avfIntermediate = 4
+	avfExpert       = 5
+	avfCustom       = 6
+	avfTime         = "02.01.2006.15:04:05"
+)
+
+var avfActions = map[mouseAction]byte{
+	mouseMove:     1,
+	leftPress:     3,
+	leftRelease:   5,
+	rightPress:    9,
+	rightRelease:  17,
+	middlePress:   33,
+	middleRelease: 65,
+}
+
+// DecodeAVF reads an AVF video, only square boards can be played.
+func DecodeAVF(r io.Reader) (replay.Log, error) {
+	br := bufio.NewReader(r)
+	var header [6]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return replay.Log{}, fmt.Errorf("can't read avf header: %w", err)
+	}
+	var width, height, count int
+	switch header[5] {
+	case avfBeginner:
+		width, height, count = 8, 8, 10
+	case avfIntermediate:
+		width, height, count = 16, 16, 40
+	case avfExpert:
+		width, height, count = 30, 16, 99
+	case avfCustom:
+		var custom [4]byte
+		if _, err := io.ReadFull(br, custom[:]); err != nil {
+			return replay.Log{}, fmt.Errorf("can't read avf board size: %w", err)
+		}
+		width, height, count = int(custom[0])+1, int(custom[1])+1, int(binary.BigEndian.Uint16(custom[2:]))
+	default:
+		return replay.Log{}, fmt.Errorf("unknown avf level %d", header[5])
+	}
+	if width != height {
+		return replay.Log{}, fmt.Errorf("board is %dx%d, only square boards are supported", width, height)
+	}
+	mines := make([]byte, 2*count)
+	if _, err := io.ReadFull(br, mines); err != nil {
+		return replay.Log{}, fmt.Errorf("can't read %d mines: %w", count, err)
+	}
+	blackHoles := make([]model.Point, 0, count)
+	for i := 0; i < count; i++ {
+		blackHoles = append(blackHoles, model.NewPoint(int(mines[2*i+1])-1, int(mines[2*i])-1))
+	}
+
+	if _, err := br.ReadString('['); err != nil {
+		return replay.Log{}, fmt.Errorf("can't find the avf times: %w", err)
+	}
+	times, err := br.ReadString(']')
+	if err != nil {
+		return replay.Log{}, fmt.Errorf("can't read the avf times: %w", err)
+	}
+	// the start time stays zero when the video was written with another date format
+	start, _, _ := strings.Cut(strings.TrimSuffix(times, "]"), "|")
+	startedAt, _ := time.Parse(avfTime, start)
+	log, err := newLog("avf", width, blackHoles, startedAt)
+	if err != nil {
+		return replay.Log{}, err
+	}
+
+	actions := map[byte]mouseAction{}
+	for a, b := range avfActions {
+		actions[b] = a
+	}
+	var mouse []mouseEvent
+	for {
+		var e [8]byte
+		if _, err := io.ReadFull(br, e[:]); err != nil {
+			break
+		}
+		action, ok := actions[e[0]]
+		if !ok {
+			break
+		}
+		seconds := int(e[6])<<8 | int(e[2])
+		mouse = append(mouse, mouseEvent{
+			at:     time.Duration(seconds-1)*time.Second + time.Duration(e[4])*10*time.Millisecond,
+			action: action,
+			x:      int(e[1])<<8 | int(e[3]),
+			y:      int(e[5])<<8 | int(e[7]),
+		})
+	}
+	log.Events = fromMouse(log.Header.Board, mouse)
+	return log, nil
+}
+
+// EncodeAVF writes the log as an AVF video, the times are cut to hundredths of a second.
+func EncodeAVF(w io.Writer, log replay.Log) error {
+	mouse, err := toMouse(log)
+	if err != nil {
+		return err
+	}
+	board := log.Header.Board
+	blackHoles := board.GetBlackHoles()
+	data := make([]byte, 5, 64)
+	switch size := board.GetSize(); {
+	case size == 8 && len(blackHoles) == 10:
+		data = append(data, avfBeginner)
+	case size == 16 && len(blackHoles) == 40:
+		data = append(data, avfIntermediate)
+	default:
+		data = append(data, avfCustom, byte(size-1), byte(size-1))
+		data = binary.BigEndian.AppendUint16(data, uint16(len(blackHoles)))
+	}
+	for _, p := range blackHoles {
+		data = append(data, byte(p.Y()+1), byte(p.X()+1))
+	}
+
+	var end time.Duration
+	if len(mouse) > 0 {
+		end = mouse[len(mouse)-1].at
+	}
+	startedAt := log.Header.StartedAt.UTC()
+	data = fmt.Appendf(data, "[%s|%s]", startedAt.Format(avfTime), startedAt.Add(end).Format(avfTime))
+	for _, m := range mouse {
+		hundredths := int(m.at / (10 * time.Millisecond))
+		seconds := hundredths/100 + 1
+		data = append(data, avfActions[m.action], byte(m.x>>8), byte(seconds), byte(m.x), byte(hundredths%100),
+			byte(m.y>>8), byte(seconds>>8), byte(m.y))
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,115 @@
+package video
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestAVF_RoundTrip(t *testing.T) {
+	log := videoLog(t, "")
+	var buf bytes.Buffer
+	if err := EncodeAVF(&buf, log); err != nil {
+		t.Fatalf("EncodeAVF() error = %v", err)
+	}
+	header := []byte{0, 0, 0, 0, 0, avfCustom, 3, 3, 0, 4, 1, 1, 2, 1, 3, 4, 4, 2}
+	if !bytes.HasPrefix(buf.Bytes(), header) {
+		t.Errorf("EncodeAVF() = %v, want to start with %v", buf.Bytes(), header)
+	}
+	times := "[01.03.2023.10:00:00|01.03.2023.10:01:05]"
+	if !bytes.Contains(buf.Bytes(), []byte(times)) {
+		t.Errorf("EncodeAVF() = %q, want times %s", buf.Bytes(), times)
+	}
+
+	got, err := DecodeAVF(&buf)
+	if err != nil {
+		t.Fatalf("DecodeAVF() error = %v", err)
+	}
+	if got.Header.Board.Layout() != log.Header.Board.Layout() {
+		t.Errorf("DecodeAVF() board:\n%s\nwant:\n%s", got.Header.Board.Layout(), log.Header.Board.Layout())
+	}
+	if got.Header.Difficulty != log.Header.Difficulty || !got.Header.StartedAt.Equal(log.Header.StartedAt) {
+		t.Errorf("DecodeAVF() header = %+v, want %+v", got.Header, log.Header)
+	}
+	if !reflect.DeepEqual(got.Events, log.Events) {
+		t.Errorf("DecodeAVF() events = %+v, want %+v", got.Events, log.Events)
+	}
+}
+
+func TestAVF_Levels(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		wantSize int
+	}{
+		{name: "beginner", data: append([]byte{0, 0, 0, 0, 0, avfBeginner}, mines(10)...), wantSize: 8},
+		{name: "intermediate", data: append([]byte{0, 0, 0, 0, 0, avfIntermediate}, mines(40)...), wantSize: 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, err := DecodeAVF(bytes.NewReader(append(tt.data, "[|]"...)))
+			if err != nil {
+				t.Fatalf("DecodeAVF() error = %v", err)
+			}
+			if size := log.Header.Board.GetSize(); size != tt.wantSize || len(log.Events) != 0 {
+				t.Errorf("DecodeAVF() size %d, %d events, want size %d", size, len(log.Events), tt.wantSize)
+			}
+		})
+	}
+}
+
+// mines returns the AVF mines on the first cells of the first rows, 8 per row.
+func mines(count int) []byte {
+	var data []byte
+	for i := 0; i < count; i++ {
+		data = append(data, byte(i/8+1), byte(i%8+1))
+	}
+	return data
+}
+
+func TestDecodeAVF_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		errorMessage string
+	}{
+		{
+			name:         "truncated header",
+			data:         []byte{0, 0, 0},
+			errorMessage: "can't read avf header: unexpected EOF",
+		},
+		{
+			name:         "unknown level",
+			data:         []byte{0, 0, 0, 0, 0, 9},
+			errorMessage: "unknown avf level 9",
+		},
+		{
+			name:         "expert",
+			data:         []byte{0, 0, 0, 0, 0, avfExpert},
+			errorMessage: "board is 30x16, only square boards are supported",
+		},
+		{
+			name:         "truncated mines",
+			data:         []byte{0, 0, 0, 0, 0, avfCustom, 7, 7, 0, 2, 1, 1},
+			errorMessage: "can't read 2 mines: unexpected EOF",
+		},
+		{
+			name:         "mine outside",
+			data:         []byte{0, 0, 0, 0, 0, avfCustom, 7, 7, 0, 1, 1, 9, '[', '|', ']'},
+			errorMessage: "invalid avf board: black hole 8,0 is outside of the 8x8 board",
+		},
+		{
+			name:         "no times",
+			data:         []byte{0, 0, 0, 0, 0, avfCustom, 7, 7, 0, 1, 1, 1},
+			errorMessage: "can't find the avf times: EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeAVF(bytes.NewReader(tt.data))
+			if err == nil || err.Error() != tt.errorMessage {
+				t.Errorf("DecodeAVF() error = %v, want %s", err, tt.errorMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package video
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"io"
+	"time"
+)
+
+// An RMV video starts with *rmv, the format version 1 and the big endian sizes of its parts: the result string,
+// the version info, the player info, the board, the preflags and the properties as uint16, the video as uint32
+// and the checksum as uint16. The parts follow in that order. The player info is the player name here. The board
+// is 4 bytes not used here, the width, the height, the number of mines as uint16 and the column and the row of
+// every mine. The video is the mouse events: the event type, 1 move, 2 left press, 3 left release, 4 right press,
+// 5 right release, 6 middle press or 7 middle release, the milliseconds as uint24 and x and y as uint16.
+const (
+	rmvMagic      = "*rmv"
+	rmvVersion    = 1
+	rmvHeaderSize = 24
+	rmvEventSize  = 8
+	rmvWriter     = "galaxy_tramp"
+)
+
+// DecodeRMV reads an RMV video, only square boards can be played.
+func DecodeRMV(r io.Reader) (replay.Log, error) {
+	var header [rmvHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return replay.Log{}, fmt.Errorf("can't read rmv header: %w", err)
+	}
+	if string(header[:4]) != rmvMagic {
+		return replay.Log{}, fmt.Errorf("not an rmv video")
+	}
+	if v := binary.BigEndian.Uint16(header[4:]); v != rmvVersion {
+		return replay.Log{}, fmt.Errorf("unsupported rmv version %d", v)
+	}
+	sizes := []int{
+		int(binary.BigEndian.Uint16(header[6:])),
+		int(binary.BigEndian.Uint16(header[8:])),
+		int(binary.BigEndian.Uint16(header[10:])),
+		int(binary.BigEndian.Uint16(header[12:])),
+		int(binary.BigEndian.Uint16(header[14:])),
+		int(binary.BigEndian.Uint16(header[16:])),
+		int(binary.BigEndian.Uint32(header[18:])),
+	}
+	parts := make([][]byte, len(sizes))
+	for i, size := range sizes {
+		parts[i] = make([]byte, size)
+		if _, err := io.ReadFull(r, parts[i]); err != nil {
+			return replay.Log{}, fmt.Errorf("can't read rmv video: %w", err)
+		}
+	}
+	player, board, video := parts[2], parts[3], parts[6]
+
+	if len(board) < 8 {
+		return replay.Log{}, fmt.Errorf("rmv board is cut short")
+	}
+	width, height, count := int(board[4]), int(board[5]), int(binary.BigEndian.Uint16(board[6:]))
+	if width != height {
+		return replay.Log{}, fmt.Errorf("board is %dx%d, only square boards are supported", width, height)
+	}
+	if len(board) != 8+2*count {
+		return replay.Log{}, fmt.Errorf("rmv board is cut short")
+	}
+	blackHoles := make([]model.Point, 0, count)
+	for i := 8; i < len(board); i += 2 {
+		blackHoles = append(blackHoles, model.NewPoint(int(board[i]), int(board[i+1])))
+	}
+	log, err := newLog("rmv", width, blackHoles, time.Time{})
+	if err != nil {
+		return replay.Log{}, err
+	}
+	log.Header.Player = string(player)
+
+	var mouse []mouseEvent
+	for i := 0; i < len(video); i += rmvEventSize {
+		e := video[i:]
+		if e[0] < byte(mouseMove) || e[0] > byte(middleRelease) {
+			return replay.Log{}, fmt.Errorf("unsupported rmv event type %d", e[0])
+		}
+		if len(e) < rmvEventSize {
+			return replay.Log{}, fmt.Errorf("rmv event is cut short")
+		}
+		milliseconds := int(e[1])<<16 | int(e[2])<<8 | int(e[3])
+		mouse = append(mouse, mouseEvent{
+			at:     time.Duration(milliseconds) * time.Millisecond,
+			action: mouseAction(e[0]),
+			x:      int(binary.BigEndian.Uint16(e[4:])),
+			y:      int(binary.BigEndian.Uint16(e[6:])),
+		})
+	}
+	log.Events = fromMouse(log.Header.Board, mouse)
+	return log, nil
+}
+
+// EncodeRMV writes the log as an RMV video, the times are cut to milliseconds.
+func EncodeRMV(w io.Writer, log replay.Log) error {
+	mouse, err := toMouse(log)
+	if err != nil {
+		return err
+	}
+	b := log.Header.Board
+	blackHoles := b.GetBlackHoles()
+	board := []byte{0, 0, 0, 0, byte(b.GetSize()), byte(b.GetSize())}
+	board = binary.BigEndian.AppendUint16(board, uint16(len(blackHoles)))
+	for _, p := range blackHoles {
+		board = append(board, byte(p.X()), byte(p.Y()))
+	}
+	var video []byte
+	for _, m := range mouse {
+		milliseconds := int(m.at / time.Millisecond)
+		video = append(video, byte(m.action), byte(milliseconds>>16), byte(milliseconds>>8), byte(milliseconds))
+		video = binary.BigEndian.AppendUint16(video, uint16(m.x))
+		video = binary.BigEndian.AppendUint16(video, uint16(m.y))
+	}
+
+	data := []byte(rmvMagic)
+	data = binary.BigEndian.AppendUint16(data, rmvVersion)
+	// result string, version info, player info, board, preflags and properties
+	for _, size := range []int{0, len(rmvWriter), len(log.Header.Player), len(board), 0, 0} {
+		data = binary.BigEndian.AppendUint16(data, uint16(size))
+	}
+	data = binary.BigEndian.AppendUint32(data, uint32(len(video)))
+	// no checksum
+	data = binary.BigEndian.AppendUint16(data, 0)
+	data = append(data, rmvWriter...)
+	data = append(data, log.Header.Player...)
+	data = append(data, board...)
+	data = append(data, video...)
+	_, err = w.Write(data)
+	return err
+}
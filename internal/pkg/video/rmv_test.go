@@ -0,0 +1,107 @@
+package video
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestRMV_RoundTrip(t *testing.T) {
+	log := videoLog(t, "ann")
+	var buf bytes.Buffer
+	if err := EncodeRMV(&buf, log); err != nil {
+		t.Fatalf("EncodeRMV() error = %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("*rmv\x00\x01")) {
+		t.Errorf("EncodeRMV() = %q, want the rmv magic", buf.Bytes())
+	}
+
+	got, err := DecodeRMV(&buf)
+	if err != nil {
+		t.Fatalf("DecodeRMV() error = %v", err)
+	}
+	if got.Header.Board.Layout() != log.Header.Board.Layout() {
+		t.Errorf("DecodeRMV() board:\n%s\nwant:\n%s", got.Header.Board.Layout(), log.Header.Board.Layout())
+	}
+	if got.Header.Difficulty != log.Header.Difficulty || got.Header.Player != "ann" {
+		t.Errorf("DecodeRMV() header = %+v, want %+v", got.Header, log.Header)
+	}
+	if !reflect.DeepEqual(got.Events, log.Events) {
+		t.Errorf("DecodeRMV() events = %+v, want %+v", got.Events, log.Events)
+	}
+}
+
+// rmv returns a video with the board and the video parts.
+func rmv(board, video []byte) []byte {
+	data := []byte("*rmv\x00\x01")
+	for _, size := range []int{0, 0, 0, len(board), 0, 0} {
+		data = binary.BigEndian.AppendUint16(data, uint16(size))
+	}
+	data = binary.BigEndian.AppendUint32(data, uint32(len(video)))
+	data = binary.BigEndian.AppendUint16(data, 0)
+	return append(append(data, board...), video...)
+}
+
+func TestDecodeRMV_Errors(t *testing.T) {
+	board := []byte{0, 0, 0, 0, 8, 8, 0, 1, 0, 0}
+	tests := []struct {
+		name         string
+		data         []byte
+		errorMessage string
+	}{
+		{
+			name:         "truncated header",
+			data:         []byte("*rmv"),
+			errorMessage: "can't read rmv header: unexpected EOF",
+		},
+		{
+			name:         "not rmv",
+			data:         append([]byte("*avf"), make([]byte, 20)...),
+			errorMessage: "not an rmv video",
+		},
+		{
+			name:         "version",
+			data:         append([]byte("*rmv\x00\x02"), make([]byte, 18)...),
+			errorMessage: "unsupported rmv version 2",
+		},
+		{
+			name:         "truncated part",
+			data:         rmv(board, nil)[:rmvHeaderSize+4],
+			errorMessage: "can't read rmv video: unexpected EOF",
+		},
+		{
+			name:         "not square",
+			data:         rmv([]byte{0, 0, 0, 0, 30, 16, 0, 0}, nil),
+			errorMessage: "board is 30x16, only square boards are supported",
+		},
+		{
+			name:         "truncated board",
+			data:         rmv(board[:9], nil),
+			errorMessage: "rmv board is cut short",
+		},
+		{
+			name:         "mine twice",
+			data:         rmv([]byte{0, 0, 0, 0, 8, 8, 0, 2, 1, 1, 1, 1}, nil),
+			errorMessage: "invalid rmv board: black hole 1,1 is listed twice",
+		},
+		{
+			name:         "board event",
+			data:         rmv(board, []byte{8, 0, 0, 0, 0, 0, 0, 0}),
+			errorMessage: "unsupported rmv event type 8",
+		},
+		{
+			name:         "truncated event",
+			data:         rmv(board, []byte{1, 0, 0, 0}),
+			errorMessage: "rmv event is cut short",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeRMV(bytes.NewReader(tt.data))
+			if err == nil || err.Error() != tt.errorMessage {
+				t.Errorf("DecodeRMV() error = %v, want %s", err, tt.errorMessage)
+			}
+		})
+	}
+}
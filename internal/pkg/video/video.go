@@ -0,0 +1,136 @@
+// Package video reads and writes the videos of the desktop minesweeper tools, the AVF videos of
+// Minesweeper Arbiter and the RMV videos of Vienna Minesweeper, as replay logs.
+//
+// The videos record the mouse over cells of 16 pixels. A left button release opens the cell, a right
+// button press flags it, a middle button release or the release of one of both pressed buttons chords it.
+// The clicks that don't change the board, like a left click on an opened number, aren't kept in the log.
+// Both formats start from a closed board, so the undo, redo and the logs of resumed games can't be written.
+package video
+
+import (
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"time"
+)
+
+const cellPixels = 16
+
+// mouseAction values are the event types of the RMV videos.
+type mouseAction int
+
+const (
+	mouseMove mouseAction = iota + 1
+	leftPress
+	leftRelease
+	rightPress
+	rightRelease
+	middlePress
+	middleRelease
+)
+
+// mouseEvent is a mouse event of a video, x and y are the pixels from the top left corner of the board.
+type mouseEvent struct {
+	at     time.Duration
+	action mouseAction
+	x, y   int
+}
+
+// toMouse returns the mouse events playing the log, clicking the middle of the cells.
+func toMouse(log replay.Log) ([]mouseEvent, error) {
+	if b := log.Header.Board; b.GetOpenedCount() > 0 || b.GetFlagsCount() > 0 {
+		return nil, fmt.Errorf("the replay starts in the middle of a game, videos start from a closed board")
+	}
+	var mouse []mouseEvent
+	for _, e := range log.Events {
+		var actions []mouseAction
+		switch e.Action {
+		case replay.Move:
+			actions = []mouseAction{mouseMove}
+		case replay.Open:
+			actions = []mouseAction{leftPress, leftRelease}
+		case replay.Flag:
+			actions = []mouseAction{rightPress, rightRelease}
+		case replay.Chord:
+			actions = []mouseAction{middlePress, middleRelease}
+		default:
+			return nil, fmt.Errorf("%s can't be played with the mouse", e.Action)
+		}
+		for _, a := range actions {
+			mouse = append(mouse, mouseEvent{at: e.At, action: a, x: e.X*cellPixels + cellPixels/2, y: e.Y*cellPixels + cellPixels/2})
+		}
+	}
+	return mouse, nil
+}
+
+// fromMouse plays the mouse events on a copy of the board and returns the log events of those changing it.
+func fromMouse(board model.Board, mouse []mouseEvent) []replay.Event {
+	b := board.Clone()
+	var changes int
+	b.Subscribe(func(model.Event) { changes++ })
+
+	var events []replay.Event
+	var left, right, chorded bool
+	lastX, lastY := -1, -1
+	for _, m := range mouse {
+		x, y := m.x/cellPixels, m.y/cellPixels
+		onBoard := m.x >= 0 && m.y >= 0 && x < b.GetSize() && y < b.GetSize()
+		var action replay.Action
+		switch m.action {
+		case mouseMove:
+			if x != lastX || y != lastY {
+				action = replay.Move
+			}
+		case leftPress:
+			left = true
+		case rightPress:
+			right = true
+			if !left {
+				action = replay.Flag
+			}
+		case leftRelease, rightRelease:
+			if left && right {
+				action, chorded = replay.Chord, true
+			} else if m.action == leftRelease && !chorded {
+				action = replay.Open
+			}
+			if m.action == leftRelease {
+				left = false
+			} else {
+				right = false
+			}
+			if !left && !right {
+				chorded = false
+			}
+		case middleRelease:
+			action = replay.Chord
+		}
+		if !onBoard {
+			continue
+		}
+		lastX, lastY = x, y
+		if action == "" {
+			continue
+		}
+		e := replay.Event{At: m.at, Action: action, X: x, Y: y}
+		before := changes
+		e.Apply(&b)
+		if action == replay.Move || changes != before {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// newLog returns the log of the board with the black holes at the points.
+func newLog(format string, size int, blackHoles []model.Point, startedAt time.Time) (replay.Log, error) {
+	board, err := model.NewBoardFromLayout(size, blackHoles)
+	if err != nil {
+		return replay.Log{}, fmt.Errorf("invalid %s board: %w", format, err)
+	}
+	return replay.Log{Header: replay.Header{
+		Difficulty: model.CustomDifficulty(size, len(blackHoles)),
+		StartedAt:  startedAt,
+		Board:      board,
+	}}, nil
+}
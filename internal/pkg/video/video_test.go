@@ -0,0 +1,138 @@
+package video
+
+import (
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const videoLayout = `
+	* 2 0 0
+	* 2 1 1
+	2 2 2 *
+	1 * 2 1
+	`
+
+// videoLog returns a log of the layout where every event changes the board or moves the cursor.
+func videoLog(t *testing.T, player string) replay.Log {
+	t.Helper()
+	board, err := model.ParseLayout(videoLayout)
+	if err != nil {
+		t.Fatalf("ParseLayout() error = %v", err)
+	}
+	return replay.Log{
+		Header: replay.Header{
+			Difficulty: model.CustomDifficulty(4, 4),
+			Player:     player,
+			StartedAt:  time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC),
+			Board:      board,
+		},
+		Events: []replay.Event{
+			{At: time.Second, Action: replay.Move, X: 3},
+			{At: 1500 * time.Millisecond, Action: replay.Open, X: 3},
+			{At: 2 * time.Second, Action: replay.Move, X: 3, Y: 2},
+			{At: 2250 * time.Millisecond, Action: replay.Flag, X: 3, Y: 2},
+			{At: 3 * time.Second, Action: replay.Move, X: 2, Y: 1},
+			// the 1 has its flag, the chord opens the two cells under it
+			{At: 3500 * time.Millisecond, Action: replay.Chord, X: 2, Y: 1},
+			{At: 65 * time.Second, Action: replay.Move, X: 1, Y: 3},
+		},
+	}
+}
+
+// click returns the mouse events of the actions on the middle of the cell.
+func click(x, y int, actions ...mouseAction) []mouseEvent {
+	var mouse []mouseEvent
+	for _, a := range actions {
+		mouse = append(mouse, mouseEvent{action: a, x: x*cellPixels + cellPixels/2, y: y*cellPixels + cellPixels/2})
+	}
+	return mouse
+}
+
+func TestFromMouse(t *testing.T) {
+	tests := []struct {
+		name  string
+		mouse []mouseEvent
+		want  []replay.Event
+	}{
+		{
+			name:  "left click opens",
+			mouse: click(3, 3, leftPress, leftRelease),
+			want:  []replay.Event{{Action: replay.Open, X: 3, Y: 3}},
+		},
+		{
+			name:  "right press flags",
+			mouse: click(0, 0, rightPress, rightRelease),
+			want:  []replay.Event{{Action: replay.Flag}},
+		},
+		{
+			name:  "both buttons chord",
+			mouse: click(2, 1, leftPress, rightPress, leftRelease, rightRelease),
+			want:  []replay.Event{{Action: replay.Chord, X: 2, Y: 1}},
+		},
+		{
+			name:  "middle button chords",
+			mouse: click(2, 1, middlePress, middleRelease),
+			want:  []replay.Event{{Action: replay.Chord, X: 2, Y: 1}},
+		},
+		{
+			name:  "click on an opened number changes nothing",
+			mouse: click(2, 1, leftPress, leftRelease),
+		},
+		{
+			name:  "flagged cell doesn't open",
+			mouse: click(3, 2, leftPress, leftRelease),
+		},
+		{
+			name: "moves inside of a cell",
+			mouse: []mouseEvent{
+				{action: mouseMove, x: 0, y: 16},
+				{action: mouseMove, x: 15, y: 31},
+				{action: mouseMove, x: 16, y: 31},
+			},
+			want: []replay.Event{{Action: replay.Move, Y: 1}, {Action: replay.Move, X: 1, Y: 1}},
+		},
+		{
+			name: "outside of the board",
+			mouse: []mouseEvent{
+				{action: mouseMove, x: -1, y: 8},
+				{action: leftPress, x: 64, y: 8},
+				{action: leftRelease, x: 64, y: 8},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := videoLog(t, "").Header.Board
+			board.Open(3, 0)
+			board.ToggleFlag(3, 2)
+			if got := fromMouse(board, tt.mouse); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fromMouse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToMouse_Errors(t *testing.T) {
+	undo := videoLog(t, "")
+	undo.Events = append(undo.Events, replay.Event{Action: replay.Undo})
+	resumed := videoLog(t, "")
+	resumed.Header.Board.Open(3, 0)
+	tests := []struct {
+		name         string
+		log          replay.Log
+		errorMessage string
+	}{
+		{name: "undo", log: undo, errorMessage: "undo can't be played with the mouse"},
+		{name: "resumed", log: resumed, errorMessage: "the replay starts in the middle of a game, videos start from a closed board"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := toMouse(tt.log); err == nil || err.Error() != tt.errorMessage {
+				t.Errorf("toMouse() error = %v, want %s", err, tt.errorMessage)
+			}
+		})
+	}
+}
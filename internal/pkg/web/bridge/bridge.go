@@ -0,0 +1,158 @@
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"strings"
+	"time"
+)
+
+// Options picks the board like the flags of the game: the code, the daily board, or the difficulty
+// with the custom size and black holes count. A zero seed picks a random one.
+type Options struct {
+	Difficulty      string `json:"difficulty"`
+	Size            int    `json:"size"`
+	BlackHolesCount int    `json:"blackHolesCount"`
+	// Seed is a string, JavaScript numbers lose the seeds above 2^53
+	Seed int64  `json:"seed,string"`
+	Code string `json:"code"`
+}
+
+type Cell struct {
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Value string `json:"value"`
+}
+
+// State is what the page shows. Board rows are snapshot symbols: ? closed, F flag, * the opened black hole
+// and the numbers. Changed lists the cells changed by the move, black holes are only listed once the game is over.
+type State struct {
+	Size            int      `json:"size"`
+	BlackHolesCount int      `json:"blackHolesCount"`
+	State           string   `json:"state"`
+	Moves           int      `json:"moves"`
+	Flags           int      `json:"flags"`
+	ElapsedMs       int64    `json:"elapsedMs"`
+	Code            string   `json:"code,omitempty"`
+	Board           []string `json:"board"`
+	Changed         []Cell   `json:"changed,omitempty"`
+	BlackHoles      []Cell   `json:"blackHoles,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Game is the game of the page. Its methods take and return JSON, so the wasm build only passes strings to JavaScript.
+type Game struct {
+	board   model.Board
+	created bool
+	now     func() time.Time
+}
+
+func New() *Game {
+	return &Game{now: time.Now}
+}
+
+// Create starts a game of the options and returns its state.
+func (g *Game) Create(options string) string {
+	var o Options
+	if err := json.Unmarshal([]byte(options), &o); err != nil {
+		return fail(fmt.Errorf("invalid options: %w", err))
+	}
+	board, err := model.BoardOptions{Difficulty: o.Difficulty, Size: o.Size, BlackHolesCount: o.BlackHolesCount, Seed: o.Seed,
+		Code: o.Code}.NewBoard(g.now())
+	if err != nil {
+		return fail(err)
+	}
+	g.board, g.created = board, true
+	return g.reply(nil)
+}
+
+func (g *Game) Open(x, y int) string {
+	return g.play(x, y, g.board.Open)
+}
+
+func (g *Game) Flag(x, y int) string {
+	return g.play(x, y, g.board.ToggleFlag)
+}
+
+func (g *Game) Chord(x, y int) string {
+	return g.play(x, y, g.board.Chord)
+}
+
+func (g *Game) State() string {
+	if !g.created {
+		return fail(fmt.Errorf("no game, create one first"))
+	}
+	return g.reply(nil)
+}
+
+func (g *Game) play(x, y int, move func(x, y int)) string {
+	if !g.created {
+		return fail(fmt.Errorf("no game, create one first"))
+	}
+	size := g.board.GetSize()
+	if x < 0 || x >= size || y < 0 || y >= size {
+		return fail(fmt.Errorf("%d,%d is outside of the board", x, y))
+	}
+	if g.board.GetState() != model.InProgress {
+		return fail(fmt.Errorf("game is %s", g.board.GetState()))
+	}
+
+	changed := map[model.Point]bool{}
+	unsubscribe := g.board.Subscribe(func(e model.Event) {
+		switch e := e.(type) {
+		case model.CellsOpened:
+			for _, p := range e.Cells {
+				changed[p] = true
+			}
+		case model.CellFlagged:
+			changed[model.NewPoint(e.X, e.Y)] = true
+		}
+	})
+	move(x, y)
+	unsubscribe()
+	return g.reply(changed)
+}
+
+func (g *Game) reply(changed map[model.Point]bool) string {
+	b := &g.board
+	snapshot := b.Snapshot()
+	st := State{
+		Size:            b.GetSize(),
+		BlackHolesCount: b.GetBlackHolesCount(),
+		State:           b.GetState().String(),
+		Moves:           b.GetMoves(),
+		Flags:           b.GetFlagsCount(),
+		ElapsedMs:       b.GetElapsed().Milliseconds(),
+		Board:           strings.Split(strings.ReplaceAll(strings.TrimSpace(snapshot.String()), " ", ""), "\n"),
+	}
+	if c, ok := b.Code(); ok {
+		st.Code = c.String()
+	}
+	points := make([]model.Point, 0, len(changed))
+	for p := range changed {
+		points = append(points, p)
+	}
+	model.SortPoints(points)
+	for _, p := range points {
+		st.Changed = append(st.Changed, Cell{X: p.X(), Y: p.Y(), Value: string(snapshot.Get(p.X(), p.Y()).Symbol())})
+	}
+	if b.GetState() != model.InProgress {
+		for _, p := range b.GetBlackHoles() {
+			st.BlackHoles = append(st.BlackHoles, Cell{X: p.X(), Y: p.Y(), Value: string(model.BlackHoleSymbol)})
+		}
+	}
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fail(err)
+	}
+	return string(data)
+}
+
+func fail(err error) string {
+	data, _ := json.Marshal(errorResponse{Error: err.Error()})
+	return string(data)
+}
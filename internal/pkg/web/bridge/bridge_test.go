@@ -0,0 +1,172 @@
+package bridge
+
+import (
+	"encoding/json"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const layout = `
+	* 1 0 0
+	1 1 0 0
+	1 1 1 1
+	* 1 1 *
+	`
+
+var elapsed = regexp.MustCompile(`"elapsedMs":\d+`)
+
+func TestGame_moves(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves func(g *Game) string
+		want  string
+	}{
+		{
+			name:  "state",
+			moves: func(g *Game) string { return g.State() },
+			want:  `{"size":4,"blackHolesCount":3,"state":"in_progress","moves":0,"flags":0,"elapsedMs":0,"board":["????","????","????","????"]}`,
+		},
+		{
+			name:  "cascade",
+			moves: func(g *Game) string { return g.Open(3, 0) },
+			want: `{"size":4,"blackHolesCount":3,"state":"in_progress","moves":1,"flags":0,"elapsedMs":0,"board":["?100","?100","?111","????"],` +
+				`"changed":[{"x":1,"y":0,"value":"1"},{"x":2,"y":0,"value":"0"},{"x":3,"y":0,"value":"0"},{"x":1,"y":1,"value":"1"},{"x":2,"y":1,"value":"0"},` +
+				`{"x":3,"y":1,"value":"0"},{"x":1,"y":2,"value":"1"},{"x":2,"y":2,"value":"1"},{"x":3,"y":2,"value":"1"}]}`,
+		},
+		{
+			name: "flag and chord",
+			moves: func(g *Game) string {
+				g.Open(3, 0)
+				g.Flag(0, 0)
+				return g.Chord(1, 1)
+			},
+			want: `{"size":4,"blackHolesCount":3,"state":"in_progress","moves":2,"flags":1,"elapsedMs":0,"board":["F100","1100","1111","????"],` +
+				`"changed":[{"x":0,"y":1,"value":"1"},{"x":0,"y":2,"value":"1"}]}`,
+		},
+		{
+			name:  "lost shows the black holes",
+			moves: func(g *Game) string { return g.Open(3, 3) },
+			want: `{"size":4,"blackHolesCount":3,"state":"lost","moves":1,"flags":0,"elapsedMs":0,"board":["????","????","????","???*"],` +
+				`"changed":[{"x":3,"y":3,"value":"*"}],"blackHoles":[{"x":0,"y":0,"value":"*"},{"x":0,"y":3,"value":"*"},{"x":3,"y":3,"value":"*"}]}`,
+		},
+		{
+			name: "move after the end",
+			moves: func(g *Game) string {
+				g.Open(3, 3)
+				return g.Open(3, 0)
+			},
+			want: `{"error":"game is lost"}`,
+		},
+		{
+			name:  "outside of the board",
+			moves: func(g *Game) string { return g.Flag(4, 0) },
+			want:  `{"error":"4,0 is outside of the board"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, err := model.ParseLayout(layout)
+			if err != nil {
+				t.Fatalf("ParseLayout() error = %v", err)
+			}
+			g := New()
+			g.board, g.created = board, true
+			// the time isn't up to the test
+			if got := elapsed.ReplaceAllString(tt.moves(g), `"elapsedMs":0`); got != tt.want {
+				t.Errorf("got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGame_Create(t *testing.T) {
+	daily := model.DailyCode(time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)).String()
+	tests := []struct {
+		name      string
+		options   string
+		wantSize  int
+		wantHoles int
+		wantCode  string
+		wantSeed  int64
+		wantError bool
+	}{
+		{name: "default", options: `{}`, wantSize: model.Easy.Size, wantHoles: model.Easy.BlackHolesCount},
+		{name: "difficulty", options: `{"difficulty":"hard"}`, wantSize: model.Hard.Size, wantHoles: model.Hard.BlackHolesCount},
+		{name: "custom", options: `{"size":12,"blackHolesCount":20,"seed":"3"}`, wantSize: 12, wantHoles: 20, wantSeed: 3},
+		{name: "seed above 2^53", options: `{"seed":"9007199254740993"}`, wantSize: model.Easy.Size, wantHoles: model.Easy.BlackHolesCount,
+			wantSeed: 9007199254740993},
+		{name: "seed as a number", options: `{"seed":3}`, wantError: true},
+		{name: "daily", options: `{"difficulty":"daily"}`, wantSize: model.Daily.Size, wantHoles: model.Daily.BlackHolesCount, wantCode: daily},
+		{name: "code", options: `{"code":" ` + daily + ` "}`, wantSize: model.Daily.Size, wantHoles: model.Daily.BlackHolesCount, wantCode: daily},
+		{name: "unknown difficulty", options: `{"difficulty":"insane"}`, wantError: true},
+		{name: "bad code", options: `{"code":"nope"}`, wantError: true},
+		{name: "not json", options: `easy`, wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New()
+			g.now = func() time.Time { return time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC) }
+			var got struct {
+				State
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal([]byte(g.Create(tt.options)), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if (got.Error != "") != tt.wantError {
+				t.Fatalf("Create() error = %q, wantError %v", got.Error, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if got.Size != tt.wantSize || got.BlackHolesCount != tt.wantHoles || got.Code == "" {
+				t.Errorf("Create() = %+v, want size %d and %d black holes", got.State, tt.wantSize, tt.wantHoles)
+			}
+			if tt.wantCode != "" && got.Code != tt.wantCode {
+				t.Errorf("Create() code = %s, want %s", got.Code, tt.wantCode)
+			}
+			if c, err := model.ParseCode(got.Code); tt.wantSeed != 0 && (err != nil || c.Seed != tt.wantSeed) {
+				t.Errorf("Create() seed = %d, %v, want %d", c.Seed, err, tt.wantSeed)
+			}
+		})
+	}
+}
+
+// TestGame_sameBoards checks a code plays the board of the terminal version.
+func TestGame_sameBoards(t *testing.T) {
+	c := model.DailyCode(time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC))
+	board, err := c.NewBoard()
+	if err != nil {
+		t.Fatalf("NewBoard() error = %v", err)
+	}
+	board.Open(0, 0)
+	g := New()
+	g.Create(`{"code":"` + c.String() + `"}`)
+	var got State
+	if err := json.Unmarshal([]byte(g.Open(0, 0)), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	var want []string
+	for y := 0; y < board.GetSize(); y++ {
+		row := ""
+		for x := 0; x < board.GetSize(); x++ {
+			row += string(board.Snapshot().Get(x, y).Symbol())
+		}
+		want = append(want, row)
+	}
+	if !reflect.DeepEqual(got.Board, want) {
+		t.Errorf("board = %v, want %v", got.Board, want)
+	}
+}
+
+func TestGame_noGame(t *testing.T) {
+	g := New()
+	for _, got := range []string{g.State(), g.Open(0, 0), g.Flag(0, 0), g.Chord(0, 0)} {
+		if got != `{"error":"no game, create one first"}` {
+			t.Errorf("got %s, want the no game error", got)
+		}
+	}
+}
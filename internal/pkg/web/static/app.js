@@ -0,0 +1,145 @@
+"use strict";
+
+// The game runs in the wasm build of internal/pkg/web/wasm, its functions answer JSON strings.
+const boardElement = document.getElementById("board");
+const banner = document.getElementById("banner");
+const form = document.getElementById("new-game");
+const outcomes = {in_progress: "", won: "  You won!", lost: "  You fell into a black hole."};
+let game = null;
+
+function call(name, ...args) {
+    const answer = JSON.parse(galaxyTramp[name](...args));
+    if (answer.error) {
+        banner.textContent = answer.error;
+        return null;
+    }
+    return answer;
+}
+
+function render(state) {
+    game = state;
+    const over = state.state !== "in_progress";
+    const holes = new Set((state.blackHoles || []).map((c) => `${c.x},${c.y}`));
+    boardElement.style.gridTemplateColumns = `repeat(${state.size}, 2em)`;
+    boardElement.replaceChildren();
+    state.board.forEach((row, y) => {
+        [...row].forEach((symbol, x) => {
+            const cell = document.createElement("button");
+            cell.className = "cell";
+            cell.dataset.x = x;
+            cell.dataset.y = y;
+            const hole = holes.has(`${x},${y}`);
+            let label = "closed";
+            if (symbol === "?") {
+                cell.classList.add("closed");
+                if (hole) {
+                    cell.textContent = "●";
+                    cell.classList.add("hole");
+                    label = "black hole";
+                }
+            } else if (symbol === "F") {
+                cell.textContent = "⚑";
+                cell.classList.add("flag");
+                label = "flagged";
+                if (over && !hole) {
+                    cell.classList.add("wrong");
+                    label = "wrongly flagged";
+                }
+            } else if (symbol === "*") {
+                cell.textContent = "●";
+                cell.classList.add("hit");
+                label = "black hole hit";
+            } else {
+                cell.textContent = symbol === "0" ? "" : symbol;
+                cell.classList.add("n" + symbol);
+                label = symbol;
+            }
+            cell.setAttribute("aria-label", `column ${x + 1} row ${y + 1}: ${label}`);
+            boardElement.append(cell);
+        });
+    });
+    showBanner();
+}
+
+function showBanner() {
+    const seconds = Math.floor(game.elapsedMs / 1000);
+    banner.textContent = `Black holes: ${game.blackHolesCount - game.flags}  Time: ${seconds}s  Code: ${game.code}${outcomes[game.state]}`;
+}
+
+function play(action, cell) {
+    const x = Number(cell.dataset.x);
+    const y = Number(cell.dataset.y);
+    const state = call(action, x, y);
+    if (state) {
+        render(state);
+        boardElement.children[y * state.size + x].focus();
+    }
+}
+
+boardElement.addEventListener("click", (e) => {
+    const cell = e.target.closest(".cell");
+    if (!cell || !game || game.state !== "in_progress") {
+        return;
+    }
+    const symbol = game.board[cell.dataset.y][cell.dataset.x];
+    if (e.shiftKey) {
+        play("flag", cell);
+    } else if (symbol === "?") {
+        play("open", cell);
+    } else if (symbol !== "F") {
+        play("chord", cell);
+    }
+});
+
+boardElement.addEventListener("contextmenu", (e) => {
+    const cell = e.target.closest(".cell");
+    e.preventDefault();
+    if (cell && game && game.state === "in_progress") {
+        play("flag", cell);
+    }
+});
+
+form.addEventListener("submit", (e) => {
+    e.preventDefault();
+    const data = new FormData(form);
+    const options = {difficulty: data.get("difficulty")};
+    for (const name of ["size", "blackHolesCount"]) {
+        const value = Number(data.get(name));
+        if (value) {
+            options[name] = value;
+        }
+    }
+    // seeds go as strings, JavaScript numbers lose the ones above 2^53
+    const seed = data.get("seed").trim();
+    if (seed && seed !== "0") {
+        options.seed = seed;
+    }
+    const code = data.get("code").trim();
+    if (code) {
+        options.code = code;
+    }
+    const state = call("create", JSON.stringify(options));
+    if (state) {
+        render(state);
+    }
+});
+
+setInterval(() => {
+    if (game && game.state === "in_progress" && game.moves > 0) {
+        const state = call("state");
+        if (state) {
+            game = state;
+            showBanner();
+        }
+    }
+}, 500);
+
+const go = new Go();
+WebAssembly.instantiateStreaming(fetch("galaxy_tramp.wasm"), go.importObject)
+    .then((result) => {
+        go.run(result.instance);
+        form.requestSubmit();
+    })
+    .catch((err) => {
+        banner.textContent = `The game failed to load: ${err}`;
+    });
@@ -0,0 +1,39 @@
+//go:build js && wasm
+
+// The wasm build of the game for the page. It sets the galaxyTramp object with create(options), open(x, y),
+// flag(x, y), chord(x, y) and state(), options and answers are JSON strings of the bridge.
+package main
+
+import (
+	"github.com/k-sever/galaxy_tramp/internal/pkg/web/bridge"
+	"syscall/js"
+)
+
+func main() {
+	g := bridge.New()
+	move := func(play func(x, y int) string) js.Func {
+		return js.FuncOf(func(this js.Value, args []js.Value) any {
+			if len(args) != 2 || args[0].Type() != js.TypeNumber || args[1].Type() != js.TypeNumber {
+				return `{"error":"x and y should be numbers"}`
+			}
+			return play(args[0].Int(), args[1].Int())
+		})
+	}
+	js.Global().Set("galaxyTramp", js.ValueOf(map[string]any{
+		"create": js.FuncOf(func(this js.Value, args []js.Value) any {
+			options := "{}"
+			if len(args) > 0 && args[0].Type() == js.TypeString {
+				options = args[0].String()
+			}
+			return g.Create(options)
+		}),
+		"open":  move(g.Open),
+		"flag":  move(g.Flag),
+		"chord": move(g.Chord),
+		"state": js.FuncOf(func(this js.Value, args []js.Value) any {
+			return g.State()
+		}),
+	}))
+	// the functions are called by the page, main must not return
+	select {}
+}
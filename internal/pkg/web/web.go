@@ -0,0 +1,37 @@
+package web
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+)
+
+// The wasm build and the JavaScript support file of the Go version building it aren't kept in git,
+// go generate puts them next to the page before the game is built.
+//go:generate sh -c "GOOS=js GOARCH=wasm go build -o static/galaxy_tramp.wasm ./wasm"
+//go:generate sh -c "cp \"$(go env GOROOT)/lib/wasm/wasm_exec.js\" static/ 2>/dev/null || cp \"$(go env GOROOT)/misc/wasm/wasm_exec.js\" static/"
+
+const (
+	WasmFile     = "galaxy_tramp.wasm"
+	WasmExecFile = "wasm_exec.js"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the page, the scripts and the wasm build of the game.
+func Handler() http.Handler {
+	files, _ := fs.Sub(static, "static")
+	return http.FileServer(http.FS(files))
+}
+
+// Check returns an error when the wasm build isn't embedded.
+func Check() error {
+	for _, name := range []string{WasmFile, WasmExecFile} {
+		if _, err := fs.Stat(static, "static/"+name); err != nil {
+			return fmt.Errorf("%s is missing, run go generate ./internal/pkg/web before building the game", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantType   string
+	}{
+		{path: "/", wantStatus: http.StatusOK, wantType: "text/html"},
+		{path: "/app.js", wantStatus: http.StatusOK, wantType: "javascript"},
+		{path: "/style.css", wantStatus: http.StatusOK, wantType: "text/css"},
+		{path: "/web.go", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Content-Type"); !strings.Contains(got, tt.wantType) {
+				t.Errorf("Content-Type = %s, want %s", got, tt.wantType)
+			}
+		})
+	}
+}
@@ -1,29 +1,202 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"github.com/k-sever/galaxy_tramp/cli"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/config"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/save"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/score"
 	"log"
 	"os"
+	"strings"
+	"time"
 )
 
+var commands = map[string]func(args []string) error{
+	"sim":       runSimulation,
+	"replay":    runReplay,
+	"convert":   runConvert,
+	"bot":       runBot,
+	"serve":     runServe,
+	"web":       runWeb,
+	"ssh-serve": runSSHServe,
+	"race":      runRace,
+}
+
 func main() {
-	// TODO: add custom mode with arbitrary board size and holes count
-	boardSize := 8
-	blackHolesCount := 10
 	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "medium":
-			boardSize = 16
-			blackHolesCount = 40
-		case "hard":
-			boardSize = 24
-			blackHolesCount = 99
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				log.Fatalf("%+v", err)
+			}
+			return
+		}
+	}
+
+	size := flag.Int("size", 0, "custom board size")
+	holes := flag.Int("holes", 0, "custom black holes count")
+	player := flag.String("name", defaultPlayer(), "player name for the high scores")
+	practice := flag.Bool("practice", false, "practice mode: opens can be undone, games don't count for high scores")
+	code := flag.String("code", "", "board code to play, shown on the banner of every game")
+	keys := flag.String("keys", "", "key bindings preset, one of "+strings.Join(cli.Presets(), ", ")+", defaults to the one of "+cli.KeysFileName+" or "+cli.DefaultPreset)
+	theme := flag.String("theme", cli.DefaultTheme, "colour theme, one of "+cli.ThemeNames(cli.BuiltinThemes())+" or a theme of "+cli.ThemesFileName)
+	colors := flag.String("colors", "", "colour mode, one of "+cli.ColorModeNames()+", defaults to the one of "+cli.SettingsFileName)
+	glyphs := flag.String("glyphs", "", "glyph set, one of "+cli.GlyphSetNames()+", defaults to the one of "+cli.SettingsFileName)
+	lines := flag.Bool("lines", false, "screen reader friendly line mode: type commands, read the answers, nothing is drawn")
+	scoresPath := flag.String("scores", "", "high scores file, defaults to "+score.FileName+" in the user config directory")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %[1]s [flags] [easy|medium|hard|daily]\n       %[1]s sim [flags]\n       %[1]s replay [file]\n       %[1]s convert <from> <to>\n       %[1]s bot [flags]\n       %[1]s serve [flags]\n       %[1]s web [flags]\n       %[1]s ssh-serve [flags]\n       %[1]s race host [flags]\n       %[1]s race join [flags] <address>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	difficulty := model.Easy
+	if flag.NArg() > 0 {
+		if d, err := model.DifficultyByName(flag.Arg(0)); err == nil {
+			difficulty = d
+		}
+	}
+	difficulty = difficulty.Custom(*size, *holes)
+
+	var boardCode *model.Code
+	if flag.Arg(0) == model.Daily.Name {
+		c := model.DailyCode(time.Now())
+		boardCode = &c
+		difficulty = model.Daily
+	}
+	if *code != "" {
+		c, err := model.ParseCode(*code)
+		if err != nil {
+			log.Fatalf("%+v", err)
+		}
+		boardCode = &c
+		difficulty = model.CustomDifficulty(c.Size, c.BlackHolesCount)
+	}
+
+	scores, err := openScores(*scoresPath)
+	if err != nil {
+		log.Printf("high scores are disabled: %v", err)
+	}
+
+	var saves *save.Store
+	if s, err := save.DefaultStore(); err == nil {
+		saves = &s
+	} else {
+		log.Printf("saving is disabled: %v", err)
+	}
+
+	replays, err := replay.DefaultDir()
+	if err != nil {
+		log.Printf("recording is disabled: %v", err)
+	}
+
+	bindings, err := loadBindings(*keys)
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+
+	cfg, err := displayConfig(*theme, *colors, *glyphs)
+	if err != nil {
+		log.Fatalf("%+v", err)
+	}
+	cfg.Difficulty, cfg.Player, cfg.Scores, cfg.Saves, cfg.Replays = difficulty, *player, scores, saves, replays
+	cfg.Practice, cfg.Code, cfg.Keys = *practice, boardCode, bindings
+	if *lines {
+		game, err := cli.NewLineGame(cfg, os.Stdin, os.Stdout)
+		if err == nil {
+			err = game.Start()
+		}
+		if err != nil {
+			log.Fatalf("%+v", err)
 		}
+		return
 	}
-	game, err := cli.NewGame(boardSize, blackHolesCount)
+
+	game, err := cli.NewGame(cfg)
 	if err != nil {
 		log.Fatalf("%+v", err)
 	}
 
 	game.Start()
 }
+
+func openScores(path string) (*score.Store, error) {
+	if path != "" {
+		s := score.NewStore(path)
+		return &s, nil
+	}
+	s, err := score.DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// loadBindings reads the key bindings from the user config directory, the preset overrides the one of the file.
+func loadBindings(preset string) (cli.Bindings, error) {
+	path, err := config.Path(cli.KeysFileName)
+	if err == nil {
+		return cli.LoadBindings(path, preset)
+	}
+	log.Printf("key bindings config is disabled: %v", err)
+	if preset == "" {
+		preset = cli.DefaultPreset
+	}
+	return cli.NewBindings(preset, nil)
+}
+
+// displayConfig returns the config of the themes, the theme, the colour mode and the glyph set,
+// the colour mode and the glyph set default to the settings file.
+func displayConfig(theme, colors, glyphs string) (cli.Config, error) {
+	cfg := cli.Config{Theme: theme}
+	var err error
+	if cfg.Colors, err = cli.ParseColorMode(orSetting(colors, func(s cli.Settings) string { return s.Colors })); err != nil {
+		return cli.Config{}, err
+	}
+	if cfg.Glyphs, err = cli.ParseGlyphSet(orSetting(glyphs, func(s cli.Settings) string { return s.Glyphs })); err != nil {
+		return cli.Config{}, err
+	}
+	if cfg.Themes, err = loadThemes(); err != nil {
+		return cli.Config{}, err
+	}
+	return cfg, nil
+}
+
+// orSetting returns the flag value if set, the value of the settings file otherwise.
+func orSetting(value string, setting func(s cli.Settings) string) string {
+	if value != "" {
+		return value
+	}
+	path, err := config.Path(cli.SettingsFileName)
+	if err != nil {
+		return ""
+	}
+	s, err := cli.LoadSettings(path)
+	if err != nil {
+		log.Printf("settings are ignored: %v", err)
+		return ""
+	}
+	return setting(s)
+}
+
+// loadThemes returns the built-in themes and the ones of the user config directory.
+func loadThemes() ([]cli.Theme, error) {
+	path, err := config.Path(cli.ThemesFileName)
+	if err != nil {
+		log.Printf("user themes are disabled: %v", err)
+		return cli.BuiltinThemes(), nil
+	}
+	return cli.LoadThemes(path)
+}
+
+func defaultPlayer() string {
+	for _, env := range []string{"USER", "USERNAME"} {
+		if name := os.Getenv(env); name != "" {
+			return name
+		}
+	}
+	return "player"
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/cli"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/race"
+	"net"
+	"os"
+)
+
+const defaultRacePort = "7777"
+
+// runRace hosts or joins a race on the local network: everyone plays the same board, the first to clear it wins.
+func runRace(args []string) error {
+	if len(args) == 0 || (args[0] != "host" && args[0] != "join") {
+		return errors.New("usage: race host [flags] or race join [flags] <address>")
+	}
+	fs := flag.NewFlagSet("race "+args[0], flag.ExitOnError)
+	name := fs.String("name", defaultPlayer(), "player name shown to the others")
+	addr := fs.String("addr", ":"+defaultRacePort, "address to listen on")
+	mode := fs.String("mode", model.Easy.Name, "difficulty of the races: easy, medium or hard")
+	countdown := fs.Int("countdown", 3, "seconds counted down before the start")
+	keys := fs.String("keys", "", "key bindings preset")
+	theme := fs.String("theme", cli.DefaultTheme, "colour theme")
+	colors := fs.String("colors", "", "colour mode, one of "+cli.ColorModeNames())
+	glyphs := fs.String("glyphs", "", "glyph set")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	// races don't count for high scores, and there's nothing to save or resume
+	cfg := cli.Config{Difficulty: model.Easy, Player: *name, Theme: *theme}
+	var err error
+	if cfg.Keys, err = loadBindings(*keys); err != nil {
+		return err
+	}
+	if cfg.Themes, err = loadThemes(); err != nil {
+		return err
+	}
+	if cfg.Colors, err = cli.ParseColorMode(orSetting(*colors, func(s cli.Settings) string { return s.Colors })); err != nil {
+		return err
+	}
+	if cfg.Glyphs, err = cli.ParseGlyphSet(orSetting(*glyphs, func(s cli.Settings) string { return s.Glyphs })); err != nil {
+		return err
+	}
+
+	join := fs.Arg(0)
+	if args[0] == "host" {
+		d, err := model.DifficultyByName(*mode)
+		if err != nil {
+			return err
+		}
+		l, err := net.Listen("tcp", *addr)
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+		server := race.NewServer(race.Config{Difficulty: d, Countdown: *countdown})
+		go server.Serve(l)
+		cfg.StartRace = server.Start
+		// the host plays over loopback like everyone else
+		_, port, _ := net.SplitHostPort(l.Addr().String())
+		join = net.JoinHostPort("127.0.0.1", port)
+		fmt.Fprintf(os.Stderr, "hosting a %s race on %s\n", d.Name, l.Addr())
+	} else if join == "" {
+		return errors.New("usage: race join [flags] <address>")
+	} else if _, _, err := net.SplitHostPort(join); err != nil {
+		join = net.JoinHostPort(join, defaultRacePort)
+	}
+
+	client, err := race.Dial(join, *name)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	cfg.Race = client
+	game, err := cli.NewGame(cfg)
+	if err != nil {
+		return err
+	}
+	game.Start()
+	return nil
+}
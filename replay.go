@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"github.com/k-sever/galaxy_tramp/cli"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/replay"
+)
+
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	theme := fs.String("theme", cli.DefaultTheme, "colour theme, one of "+cli.ThemeNames(cli.BuiltinThemes())+" or a theme of "+cli.ThemesFileName)
+	colors := fs.String("colors", "", "colour mode, one of "+cli.ColorModeNames()+", defaults to the one of "+cli.SettingsFileName)
+	glyphs := fs.String("glyphs", "", "glyph set, one of "+cli.GlyphSetNames()+", defaults to the one of "+cli.SettingsFileName)
+	fs.Usage = func() {
+		fs.Output().Write([]byte("Usage: replay [flags] [file]\nPlays back a recorded game or an AVF or RMV video, the latest game if no file is given.\n"))
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	if path == "" {
+		dir, err := replay.DefaultDir()
+		if err != nil {
+			return err
+		}
+		if path, err = replay.Latest(dir); err != nil {
+			return err
+		}
+	}
+	log, err := readReplay(path)
+	if err != nil {
+		return err
+	}
+	cfg, err := displayConfig(*theme, *colors, *glyphs)
+	if err != nil {
+		return err
+	}
+	r, err := cli.NewReplay(log, cfg)
+	if err != nil {
+		return err
+	}
+	r.Start()
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/server"
+	"log"
+	"net/http"
+	"time"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	ttl := fs.Duration("ttl", time.Hour, "how long a game is kept after its last request")
+	maxGames := fs.Int("max-games", 1000, "maximum number of games kept at once")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	log.Printf("serving games on %s", *addr)
+	return newHTTPServer(*addr, server.New(server.Config{TTL: *ttl, MaxGames: *maxGames})).ListenAndServe()
+}
+
+// newHTTPServer returns a server dropping the clients that are too slow or stay idle, so they can't hold
+// connections forever. The WebSocket upgrade clears the deadlines, the live games aren't cut by them.
+func newHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      time.Minute,
+		IdleTimeout:       2 * time.Minute,
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/sim"
+	"os"
+	"strings"
+	"time"
+)
+
+func runSimulation(args []string) error {
+	fs := flag.NewFlagSet("sim", flag.ExitOnError)
+	mode := fs.String("mode", model.Easy.Name, "difficulty preset: easy, medium or hard")
+	size := fs.Int("size", 0, "custom board size, overrides the preset")
+	holes := fs.Int("holes", 0, "custom black holes count, overrides the preset")
+	games := fs.Int("games", 1000, "number of games to play")
+	seed := fs.Int64("seed", time.Now().UnixMilli(), "master seed, every game seed is derived from it")
+	workers := fs.Int("workers", 0, "number of parallel workers, defaults to the number of CPUs")
+	strategy := fs.String("strategy", "simple", "strategy: "+strings.Join(sim.StrategyNames(), ", "))
+	format := fs.String("format", "text", "output format: text, json or csv")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	difficulty, err := model.DifficultyByName(*mode)
+	if err != nil {
+		return err
+	}
+	difficulty = difficulty.Custom(*size, *holes)
+
+	cfg := sim.Config{
+		Size:            difficulty.Size,
+		BlackHolesCount: difficulty.BlackHolesCount,
+		Games:           *games,
+		Seed:            *seed,
+		Workers:         *workers,
+		Strategy:        *strategy,
+	}
+	results, err := sim.Run(cfg)
+	if err != nil {
+		return err
+	}
+	report := sim.NewReport(cfg, results)
+
+	switch *format {
+	case "text":
+		return report.WriteText(os.Stdout)
+	case "json":
+		return report.WriteJSON(os.Stdout)
+	case "csv":
+		return report.WriteCSV(os.Stdout)
+	}
+	return fmt.Errorf("unknown format %q", *format)
+}
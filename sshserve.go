@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"github.com/gdamore/tcell/v2"
+	"github.com/k-sever/galaxy_tramp/cli"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/config"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/model"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/sshd"
+	"golang.org/x/crypto/ssh"
+	"log"
+	"os"
+	"time"
+)
+
+func runSSHServe(args []string) error {
+	fs := flag.NewFlagSet("ssh-serve", flag.ExitOnError)
+	addr := fs.String("addr", ":2222", "address to listen on")
+	mode := fs.String("mode", model.Easy.Name, "difficulty of the games: easy, medium, hard or daily")
+	hostKey := fs.String("host-key", "", "host key file, generated if missing, defaults to "+sshd.HostKeyFileName+" in the user config directory")
+	authorizedKeys := fs.String("authorized-keys", "", "authorized_keys file of the players named by the key comments, anyone can play if empty")
+	scoresPath := fs.String("scores", "", "high scores file, defaults to the one of the user config directory")
+	keys := fs.String("keys", "", "key bindings preset of every player")
+	theme := fs.String("theme", cli.DefaultTheme, "colour theme of every player")
+	colors := fs.String("colors", "", "colour mode of every player, one of "+cli.ColorModeNames())
+	glyphs := fs.String("glyphs", "", "glyph set, ascii for clients without Unicode fonts")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := displayConfig(*theme, *colors, *glyphs)
+	if err != nil {
+		return err
+	}
+	cfg.Difficulty = model.Daily
+	if *mode != model.Daily.Name {
+		if cfg.Difficulty, err = model.DifficultyByName(*mode); err != nil {
+			return err
+		}
+	}
+	if cfg.Scores, err = openScores(*scoresPath); err != nil {
+		log.Printf("high scores are disabled: %v", err)
+	}
+	if cfg.Keys, err = loadBindings(*keys); err != nil {
+		return err
+	}
+
+	if *hostKey == "" {
+		if *hostKey, err = config.Path(sshd.HostKeyFileName); err != nil {
+			return err
+		}
+	}
+	signer, err := sshd.LoadHostKey(*hostKey)
+	if err != nil {
+		return err
+	}
+	var authorized []sshd.AuthorizedKey
+	if *authorizedKeys != "" {
+		data, err := os.ReadFile(*authorizedKeys)
+		if err != nil {
+			return err
+		}
+		if authorized, err = sshd.ParseAuthorizedKeys(data); err != nil {
+			return err
+		}
+	}
+
+	// every session plays its own game, saves and replays stay off as the players share the config directory
+	play := func(player, term string, s tcell.Screen) error {
+		c := cfg
+		c.Player, c.Screen, c.Term = player, s, term
+		if c.Difficulty == model.Daily {
+			code := model.DailyCode(time.Now())
+			c.Code = &code
+		}
+		game, err := cli.NewGame(c)
+		if err != nil {
+			return err
+		}
+		log.Printf("%s started playing", player)
+		game.Start()
+		log.Printf("%s left", player)
+		return nil
+	}
+	if len(authorized) == 0 {
+		log.Printf("no authorized keys, anyone reaching %s can play under any name", *addr)
+	}
+	log.Printf("serving games over SSH on %s, host key %s", *addr, ssh.FingerprintSHA256(signer.PublicKey()))
+	return sshd.NewServer(sshd.Config{HostKey: signer, AuthorizedKeys: authorized, Play: play}).ListenAndServe(*addr)
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"flag"
+	"github.com/k-sever/galaxy_tramp/internal/pkg/web"
+	"log"
+)
+
+func runWeb(args []string) error {
+	fs := flag.NewFlagSet("web", flag.ExitOnError)
+	addr := fs.String("addr", ":8000", "address to listen on")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := web.Check(); err != nil {
+		return err
+	}
+
+	log.Printf("serving the game page on %s", *addr)
+	return newHTTPServer(*addr, web.Handler()).ListenAndServe()
+}